@@ -5,19 +5,65 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
 	"writeup-finder.go/global"
 	"writeup-finder.go/handler"
 	"writeup-finder.go/utils"
 )
 
 // ManageAction processes the list of URLs, finds new articles, and logs the results.
+// The lookback window comes from --since or --max-age and defaults to today and yesterday.
 func ManageAction() {
-	urlList := utils.ReadUrls(global.UrlFile)
-	today := time.Now()
+	window, err := LookbackWindow(time.Now())
+	utils.HandleError(err, "Invalid lookback window", true)
+
+	ProcessWindow(window)
+}
+
+// ProcessWindow processes every feed for articles published within the window and logs the results.
+func ProcessWindow(window handler.TimeWindow) {
+	urlList := LoadFeeds()
+	log.Infof("[+] Looking for articles published between %s and %s",
+		window.From.Format(time.RFC3339), window.To.Format(time.RFC3339))
 
 	// Process the URLs and store new articles in the database if enabled
-	articlesFound := handler.ProcessUrls(urlList, today, global.DB)
+	articlesFound := handler.ProcessUrls(urlList, window, global.DB)
 
 	utils.PrintPretty(fmt.Sprintf("Total new articles found: %d", articlesFound), color.FgYellow, false)
 	utils.PrintPretty("Writeup Finder Script Completed", color.FgHiYellow, true)
 }
+
+// LookbackWindow builds the window for a regular run from the --since and --max-age flags.
+func LookbackWindow(now time.Time) (handler.TimeWindow, error) {
+	switch {
+	case global.Since != "" && global.MaxAge > 0:
+		return handler.TimeWindow{}, fmt.Errorf("--since and --max-age cannot be used together")
+	case global.Since != "":
+		since, err := ParseTimeFlag(global.Since, false)
+		if err != nil {
+			return handler.TimeWindow{}, err
+		}
+		return handler.TimeWindow{From: since, To: now}, nil
+	case global.MaxAge > 0:
+		return handler.MaxAgeWindow(now, global.MaxAge), nil
+	default:
+		return handler.DefaultWindow(now), nil
+	}
+}
+
+// ParseTimeFlag parses a flag value given either as a date (2006-01-02) or as an RFC3339 timestamp.
+// A bare date is read as the start of that day, or as its last instant when endOfDay is true.
+func ParseTimeFlag(value string, endOfDay bool) (time.Time, error) {
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return parsed, nil
+	}
+
+	parsed, err := time.ParseInLocation(global.DateFormat, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected %s or RFC3339", value, global.DateFormat)
+	}
+	if endOfDay {
+		parsed = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,52 @@
+package command
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"writeup-finder.go/global"
+	"writeup-finder.go/handler"
+	"writeup-finder.go/utils"
+)
+
+// backfillCmd reprocesses the feeds for an arbitrary publication time range.
+var backfillCmd = &cobra.Command{
+	Use:   "backfill --from DATE [--to DATE]",
+	Short: "Reprocess feed items published in a past time range",
+	Long: `Backfill runs a normal pass over the feeds but accepts every item published between --from
+and --to instead of only today and yesterday. Articles already in the database are skipped, and
+Telegram messages are spaced out by --send-interval so a large catch-up does not flood the topics.
+Feeds only expose their most recent items, so the range cannot reach further back than they do.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		from, err := ParseTimeFlag(global.BackfillFrom, false)
+		utils.HandleError(err, "Invalid --from value", true)
+
+		to := time.Now()
+		if global.BackfillTo != "" {
+			to, err = ParseTimeFlag(global.BackfillTo, true)
+			utils.HandleError(err, "Invalid --to value", true)
+		}
+		if to.Before(from) {
+			log.Fatal("The --to value must not be before --from.")
+		}
+
+		PrepareRun()
+		defer FinishRun()
+
+		// Every item has to be read again, whether or not its feed changed since the last run
+		utils.FeedCache = nil
+
+		ProcessWindow(handler.TimeWindow{From: from, To: to})
+	},
+}
+
+// init registers the backfill flags.
+func init() {
+	backfillCmd.Flags().StringVar(&global.BackfillFrom, "from", "", "Start of the range, as YYYY-MM-DD or RFC3339")
+	backfillCmd.Flags().StringVar(&global.BackfillTo, "to", "", "End of the range, as YYYY-MM-DD or RFC3339 (default now)")
+	backfillCmd.MarkFlagRequired("from")
+
+	rootCmd.AddCommand(backfillCmd)
+}
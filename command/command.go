@@ -3,8 +3,10 @@ package command
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"writeup-finder.go/db"
 	"writeup-finder.go/global"
@@ -16,19 +18,13 @@ var rootCmd = &cobra.Command{
 	Use:   "writeup-finder",
 	Short: "A tool to find writeups and manage articles",
 	Long:  `Writeup-finder is a tool to search for writeups and manage article data, including sending notifications.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		LoadSettings(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if cmd.CalledAs() != "completion" {
-			// Load environment variables and flags
-			utils.LoadEnv()
-			ManageFlags()
-			utils.PrintPretty("Starting Writeup Finder Script", color.FgHiYellow, true)
-
-			// Connect to the database if enabled
-			if global.UseDatabase {
-				global.DB = db.ConnectDB()
-				db.CreateArticlesTable(global.DB)
-				defer global.DB.Close()
-			}
+			PrepareRun()
+			defer FinishRun()
 
 			// Execute main logic of the script
 			ManageAction()
@@ -36,6 +32,22 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// serveCmd keeps a single process alive and runs the finder on a fixed interval.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the finder continuously on a fixed interval",
+	Long: `Serve keeps one process alive and runs a full pass over the feeds every --interval.
+The database connection and HTTP clients are reused between passes, a tick that fires
+while the previous pass is still running is skipped, and SIGINT/SIGTERM stop the loop
+after the current pass has finished.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		PrepareRun()
+		defer FinishRun()
+
+		Serve(global.ServeInterval)
+	},
+}
+
 // Execute runs the root command, to be called in main.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -44,12 +56,51 @@ func Execute() {
 	}
 }
 
+// PrepareRun validates the flags and opens the database connection if enabled.
+func PrepareRun() {
+	ManageFlags()
+	utils.PrintPretty("Starting Writeup Finder Script", color.FgHiYellow, true)
+
+	// Connect to the database if enabled; a dry run only reads from it
+	if global.UseDatabase {
+		global.DB = db.ConnectDB()
+		if !global.DryRun {
+			applied, err := db.MigrateUp(global.DB)
+			utils.HandleError(err, "Error migrating the database schema", true)
+			log.Infof("[+] Database schema up to date (%d migrations applied).", applied)
+
+			// Feeds unchanged since the last run are skipped, unless an explicit window asks to look further back
+			if global.Since == "" && global.MaxAge == 0 {
+				utils.FeedCache = db.FeedCache{DB: global.DB}
+			}
+		}
+	}
+}
+
+// FinishRun releases the resources opened by PrepareRun.
+func FinishRun() {
+	if global.DB != nil {
+		global.DB.Close()
+	}
+}
+
 // init initializes global flags and subcommands.
 func init() {
 	rootCmd.PersistentFlags().BoolVar(&global.UseDatabase, "database", false, "Save new articles in the database")
 	rootCmd.PersistentFlags().BoolVar(&global.SendToTelegramFlag, "telegram", false, "Send new articles to Telegram")
 	rootCmd.PersistentFlags().StringVar(&global.ProxyURL, "proxy", "", "Proxy URL to use for sending Telegram messages")
+	rootCmd.PersistentFlags().BoolVar(&global.DryRun, "dry-run", false, "Fetch and classify articles but only print the messages instead of saving or sending them")
+	rootCmd.PersistentFlags().StringVar(&global.Since, "since", "", "Only process articles published after this date (YYYY-MM-DD or RFC3339)")
+	rootCmd.PersistentFlags().DurationVar(&global.MaxAge, "max-age", 0, "Only process articles published within this duration, e.g. 72h (default today and yesterday)")
+	rootCmd.PersistentFlags().IntVar(&global.Workers, "workers", 8, "Number of feeds fetched in parallel")
+	rootCmd.PersistentFlags().DurationVar(&global.SendInterval, "send-interval", 3*time.Second, "Minimum time between two Telegram messages")
+	rootCmd.PersistentFlags().StringVar(&global.ConfigPath, "config", "", "Path to the YAML config file (default $WRITEUP_FINDER_CONFIG or config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&global.UrlFile, "url-file", global.DefaultUrlFile, "Path to the list of feeds")
+	rootCmd.PersistentFlags().StringVar(&global.KeywordsFile, "keywords-file", global.DefaultKeywordsFile, "Path to the keyword rules")
 	rootCmd.PersistentFlags().BoolVar(&global.Help, "help", false, "Show help")
 
+	serveCmd.Flags().DurationVar(&global.ServeInterval, "interval", 3*time.Hour, "Time between two passes over the feeds")
+
 	rootCmd.AddCommand(completionCmd)
+	rootCmd.AddCommand(serveCmd)
 }
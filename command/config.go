@@ -0,0 +1,42 @@
+package command
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"writeup-finder.go/config"
+	"writeup-finder.go/global"
+	"writeup-finder.go/utils"
+)
+
+// LoadSettings loads the .env file and the config file, then resolves the settings that have flags.
+// Precedence is flags, then environment variables, then the config file, then built-in defaults.
+func LoadSettings(cmd *cobra.Command) {
+	utils.LoadEnv()
+
+	// An explicit path must exist, the default one is optional
+	path, required := global.ConfigPath, true
+	if path == "" {
+		path = os.Getenv("WRITEUP_FINDER_CONFIG")
+	}
+	if path == "" {
+		path, required = config.DefaultPath, false
+	}
+	utils.HandleError(config.Load(path, required), "Error loading config file", true)
+
+	flags := cmd.Flags()
+	if !flags.Changed("url-file") {
+		global.UrlFile = config.LookupOr("URL_FILE", global.DefaultUrlFile)
+	}
+	if !flags.Changed("keywords-file") {
+		global.KeywordsFile = config.LookupOr("KEYWORDS_FILE", global.DefaultKeywordsFile)
+	}
+	if !flags.Changed("proxy") && global.SendToTelegramFlag {
+		global.ProxyURL = config.Lookup("PROXY_URL")
+	}
+}
+
+// LoadFeeds returns the feeds from the URL file followed by the ones declared in the config file.
+func LoadFeeds() []string {
+	return utils.DedupeUrls(append(utils.ReadUrls(global.UrlFile), config.Current().Feeds...))
+}
@@ -0,0 +1,89 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"writeup-finder.go/db"
+	"writeup-finder.go/global"
+	"writeup-finder.go/utils"
+)
+
+// dbCmd groups the database maintenance subcommands.
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the articles database",
+}
+
+// migrateCmd groups the schema migration subcommands.
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply, revert or inspect the schema migrations",
+}
+
+// migrateUpCmd applies every pending migration.
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		database := db.ConnectDB()
+		defer database.Close()
+
+		applied, err := db.MigrateUp(database)
+		utils.HandleError(err, "Error applying migrations", true)
+		utils.PrintPretty(fmt.Sprintf("Applied %d migration(s)", applied), color.FgGreen, false)
+	},
+}
+
+// migrateDownCmd reverts the most recent migrations.
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the most recently applied migrations",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		database := db.ConnectDB()
+		defer database.Close()
+
+		reverted, err := db.MigrateDown(database, global.MigrateSteps)
+		utils.HandleError(err, "Error reverting migrations", true)
+		utils.PrintPretty(fmt.Sprintf("Reverted %d migration(s)", reverted), color.FgGreen, false)
+	},
+}
+
+// migrateStatusCmd lists the migrations and whether they are applied.
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which migrations are applied",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		database := db.ConnectDB()
+		defer database.Close()
+
+		states, err := db.MigrationStatus(database)
+		utils.HandleError(err, "Error reading migration status", true)
+
+		writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+		for _, state := range states {
+			appliedAt := "pending"
+			if state.AppliedAt != nil {
+				appliedAt = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(writer, "%04d\t%s\t%s\n", state.Version, state.Name, appliedAt)
+		}
+		writer.Flush()
+	},
+}
+
+// init registers the db subcommands.
+func init() {
+	migrateDownCmd.Flags().IntVar(&global.MigrateSteps, "steps", 1, "Number of migrations to revert")
+
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd)
+	dbCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(dbCmd)
+}
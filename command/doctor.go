@@ -0,0 +1,166 @@
+package command
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"writeup-finder.go/config"
+	"writeup-finder.go/db"
+	"writeup-finder.go/global"
+	"writeup-finder.go/telegram"
+	"writeup-finder.go/utils"
+)
+
+// RequiredSettings lists the settings every full run needs, from the environment or the config file.
+var RequiredSettings = []string{
+	"TELEGRAM_BOT_TOKEN",
+	"CHAT_ID",
+	"MAIN_THREAD_ID",
+	"YOUTUBE_THREAD_ID",
+	"DB_HOST",
+	"DB_PORT",
+	"DB_NAME",
+	"DB_USER",
+	"DB_PASSWORD",
+}
+
+// CheckResult is the outcome of one doctor check.
+type CheckResult struct {
+	Name   string
+	OK     bool
+	Detail string
+}
+
+// doctorCmd validates the configuration and the external services before a run.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the configuration, database and Telegram access",
+	Long: `Doctor runs a preflight check: every required setting, every keyword regex and thread reference,
+the database connection, the proxy URL, and the Bot API token and chats through getMe and getChat.
+The Bot API base URL can be overridden with TELEGRAM_API_URL. It exits with status 1 if any check fails.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !PrintCheckResults(RunChecks()) {
+			os.Exit(1)
+		}
+	},
+}
+
+// RunChecks runs every doctor check and returns their results in order.
+func RunChecks() []CheckResult {
+	var results []CheckResult
+	add := func(name string, err error, detail string) {
+		if err != nil {
+			results = append(results, CheckResult{Name: name, Detail: err.Error()})
+			return
+		}
+		results = append(results, CheckResult{Name: name, OK: true, Detail: detail})
+	}
+
+	// Settings
+	for _, key := range RequiredSettings {
+		if config.Lookup(key) == "" {
+			add("setting "+key, fmt.Errorf("not set in the environment or the config file"), "")
+		} else {
+			add("setting "+key, nil, "set")
+		}
+	}
+
+	// Keywords and thread references
+	if errs := utils.ValidateKeywords(global.KeywordsFile, config.Lookup); len(errs) > 0 {
+		for _, err := range errs {
+			add("keywords", err, "")
+		}
+	} else {
+		add("keywords", nil, "all patterns compile and all threads resolve")
+	}
+
+	// Database
+	database, err := db.OpenDB()
+	if err == nil {
+		database.Close()
+	}
+	add("database", err, "ping succeeded")
+
+	// Proxy
+	proxyURL := global.ProxyURL
+	if proxyURL == "" {
+		proxyURL = config.Lookup("PROXY_URL")
+	}
+	if proxyURL == "" {
+		add("proxy", nil, "no proxy configured")
+	} else {
+		add("proxy", telegram.ValidateProxyURL(proxyURL), proxyURL)
+	}
+
+	// Bot API
+	botToken := config.Lookup("TELEGRAM_BOT_TOKEN")
+	client := utils.SharedHTTPClient(proxyURL)
+	if botToken == "" {
+		add("telegram getMe", fmt.Errorf("skipped, TELEGRAM_BOT_TOKEN is not set"), "")
+		return results
+	}
+
+	user, err := telegram.GetMe(client, botToken)
+	if err != nil {
+		add("telegram getMe", err, "")
+		return results
+	}
+	add("telegram getMe", nil, "@"+user.Username)
+
+	for _, chatID := range chatIDs() {
+		chat, err := telegram.GetChat(client, botToken, chatID)
+		detail := ""
+		if err == nil {
+			detail = fmt.Sprintf("%s (%s)", chat.Title, chat.Type)
+			if !chat.IsForum {
+				err = fmt.Errorf("%s is not a forum, message threads will not work", chat.Title)
+			}
+		}
+		add("telegram getChat "+chatID, err, detail)
+	}
+
+	return results
+}
+
+// chatIDs returns the default chat and every per-topic chat from the config file, without duplicates.
+func chatIDs() []string {
+	var ids []string
+	if chatID := config.Lookup("CHAT_ID"); chatID != "" {
+		ids = append(ids, chatID)
+	}
+	for _, topic := range config.Current().Topics {
+		if topic.ChatID != "" {
+			ids = append(ids, topic.ChatID)
+		}
+	}
+	return utils.UniqueStrings(ids)
+}
+
+// PrintCheckResults prints the doctor report and returns true if every check passed.
+func PrintCheckResults(results []CheckResult) bool {
+	failed := 0
+	for _, result := range results {
+		if result.OK {
+			fmt.Printf("%s %s: %s\n", color.GreenString("[PASS]"), result.Name, result.Detail)
+		} else {
+			failed++
+			fmt.Printf("%s %s: %s\n", color.RedString("[FAIL]"), result.Name, result.Detail)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		utils.PrintPretty(fmt.Sprintf("%d of %d checks failed", failed, len(results)), color.FgRed, false)
+		return false
+	}
+	utils.PrintPretty(fmt.Sprintf("All %d checks passed", len(results)), color.FgGreen, false)
+	return true
+}
+
+// init registers the doctor command.
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
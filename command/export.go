@@ -0,0 +1,70 @@
+package command
+
+import (
+	"bufio"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"writeup-finder.go/config"
+	"writeup-finder.go/db"
+	"writeup-finder.go/export"
+	"writeup-finder.go/global"
+	"writeup-finder.go/utils"
+)
+
+// exportCmd writes the archived articles in a shareable format.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export archived articles as JSON, CSV, Markdown or OPML",
+	Long: `Export reads the articles table and writes it to stdout or --output in the chosen format.
+Rows are streamed, so large archives are exported without loading them into memory.
+--topic accepts a topic name from the config file or a thread variable such as CVE_THREAD_ID,
+and --since keeps only the articles published after the given date.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Checked first so a typo does not leave an empty output file behind
+		utils.HandleError(export.ValidateFormat(global.ExportFormat), "Invalid export format", true)
+
+		filter := db.ArticleFilter{}
+		if global.ExportTopic != "" {
+			filter.Topic = config.TopicEnv(global.ExportTopic)
+		}
+		if global.Since != "" {
+			since, err := ParseTimeFlag(global.Since, false)
+			utils.HandleError(err, "Invalid --since value", true)
+			filter.Since = since
+		} else if global.MaxAge > 0 {
+			filter.Since = time.Now().Add(-global.MaxAge)
+		}
+
+		out := os.Stdout
+		if global.ExportOutput != "" && global.ExportOutput != "-" {
+			file, err := os.Create(global.ExportOutput)
+			utils.HandleError(err, "Error creating output file", true)
+			defer file.Close()
+			out = file
+		}
+		buffered := bufio.NewWriter(out)
+		defer buffered.Flush()
+
+		writer, err := export.NewWriter(global.ExportFormat, buffered)
+		utils.HandleError(err, "Invalid export format", true)
+
+		database := db.ConnectDB()
+		defer database.Close()
+
+		err = db.StreamArticles(database, filter, writer.Write)
+		utils.HandleError(err, "Error exporting articles", true)
+		utils.HandleError(writer.Close(), "Error finishing export", true)
+	},
+}
+
+// init registers the export flags.
+func init() {
+	exportCmd.Flags().StringVar(&global.ExportFormat, "format", "json", "Output format: json, csv, markdown or opml")
+	exportCmd.Flags().StringVar(&global.ExportTopic, "topic", "", "Only export articles posted to this topic")
+	exportCmd.Flags().StringVarP(&global.ExportOutput, "output", "o", "", "Write to this file instead of stdout")
+
+	rootCmd.AddCommand(exportCmd)
+}
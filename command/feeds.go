@@ -0,0 +1,272 @@
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mmcdole/gofeed"
+	"github.com/spf13/cobra"
+	"writeup-finder.go/db"
+	"writeup-finder.go/global"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// feedsCmd groups the subcommands that manage the feed list in url.txt.
+var feedsCmd = &cobra.Command{
+	Use:   "feeds",
+	Short: "Manage the list of monitored feeds",
+}
+
+// feedsListCmd prints the feeds grouped by type.
+var feedsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the feeds grouped by type",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		PrintFeedTable(utils.ReadUrls(global.UrlFile))
+	},
+}
+
+// feedsAddCmd appends new feeds to the end of the URL file.
+var feedsAddCmd = &cobra.Command{
+	Use:   "add URL...",
+	Short: "Add one or more feeds",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		existing := make(map[string]bool)
+		for _, u := range utils.ReadUrls(global.UrlFile) {
+			existing[u] = true
+		}
+
+		var added []string
+
+		for _, arg := range args {
+			feedURL := strings.TrimSpace(arg)
+			if err := utils.ValidateFeedURL(feedURL); err != nil {
+				utils.HandleError(err, fmt.Sprintf("Invalid feed %q", arg), true)
+			}
+			if existing[feedURL] {
+				utils.PrintPretty(fmt.Sprintf("Feed already present: %s", feedURL), color.FgYellow, false)
+				continue
+			}
+			existing[feedURL] = true
+			added = append(added, feedURL)
+			utils.PrintPretty(fmt.Sprintf("Added %s feed: %s", source.Kind(feedURL), feedURL), color.FgGreen, false)
+		}
+
+		utils.HandleError(utils.AppendUrls(global.UrlFile, added), "Error writing URL file", true)
+	},
+}
+
+// feedsRemoveCmd deletes the lines of the given feeds from the URL file.
+var feedsRemoveCmd = &cobra.Command{
+	Use:   "remove URL...",
+	Short: "Remove one or more feeds",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := utils.RemoveUrls(global.UrlFile, args)
+		utils.HandleError(err, "Error writing URL file", true)
+
+		found := make(map[string]bool, len(removed))
+		for _, u := range removed {
+			utils.PrintPretty(fmt.Sprintf("Removed feed: %s", u), color.FgGreen, false)
+			found[u] = true
+		}
+		for _, arg := range args {
+			if u := strings.TrimSpace(arg); !found[u] {
+				utils.PrintPretty(fmt.Sprintf("Feed not found: %s", u), color.FgYellow, false)
+			}
+		}
+	},
+}
+
+// feedsValidateCmd checks every entry of the URL file and parses it with the source that handles it.
+var feedsValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check that every feed is well-formed, unique and parseable",
+	Long: `Validate checks every line of url.txt for stray whitespace, duplicates and malformed URLs,
+then parses each feed with the source that handles it. By default the feeds are fetched live; with --fixtures the
+parser reads <dir>/<name>.xml instead, where <name> is the feed URL with every character
+other than letters and digits replaced by an underscore.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !ValidateFeeds(global.UrlFile, global.FeedFixturesDir) {
+			os.Exit(1)
+		}
+	},
+}
+
+// feedsHealthCmd reports the feeds that are dead, stale or quarantined.
+var feedsHealthCmd = &cobra.Command{
+	Use:   "health",
+	Short: "Report dead, stale and quarantined feeds",
+	Long: `Health lists the feeds that need attention, based on the outcome of every fetch recorded in the database.
+A feed is quarantined after FEED_QUARANTINE_AFTER failures in a row (default 5) and checked again after
+an exponentially growing delay. It is dead when it is still failing and has not worked for --stale,
+and stale when it works but its newest item is older than --stale. Use --all to list healthy feeds too.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		database := db.ConnectDB()
+		defer database.Close()
+
+		feeds, err := db.ListFeedHealth(database)
+		utils.HandleError(err, "Error loading feed health", true)
+
+		PrintFeedHealth(feeds, time.Now(), global.HealthStaleAfter, global.HealthAll)
+	},
+}
+
+var fixtureNameReplacer = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// FixtureName returns the file name under which the fixture for a feed URL is looked up.
+func FixtureName(feedURL string) string {
+	return strings.Trim(fixtureNameReplacer.ReplaceAllString(feedURL, "_"), "_") + ".xml"
+}
+
+// ValidateFeeds checks the raw lines of the URL file and prints a report.
+// It returns false if any entry failed.
+func ValidateFeeds(filePath, fixturesDir string) bool {
+	content, err := os.ReadFile(filePath)
+	utils.HandleError(err, "Error opening URL file", true)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "LINE\tTYPE\tSTATUS\tURL")
+
+	ok := true
+	seen := make(map[string]int)
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lineNumber := i + 1
+		feedURL := strings.TrimSpace(line)
+
+		status := "ok"
+		switch {
+		case seen[feedURL] != 0:
+			status = fmt.Sprintf("duplicate of line %d", seen[feedURL])
+		case utils.ValidateFeedURL(line) != nil:
+			status = fmt.Sprintf("invalid: %v", utils.ValidateFeedURL(line))
+		default:
+			if err := parseFeed(feedURL, fixturesDir); err != nil {
+				status = fmt.Sprintf("unparseable: %v", err)
+			}
+		}
+		if seen[feedURL] == 0 {
+			seen[feedURL] = lineNumber
+		}
+
+		if status != "ok" {
+			ok = false
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", lineNumber, source.Kind(feedURL), status, feedURL)
+	}
+	writer.Flush()
+
+	if ok {
+		utils.PrintPretty("All feeds are valid", color.FgGreen, false)
+	} else {
+		utils.PrintPretty("Some feeds failed validation", color.FgRed, false)
+	}
+	return ok
+}
+
+// parseFeed parses a feed from its fixture file when fixturesDir is set, or from a live fetch otherwise.
+// Both go through the source that handles the feed, so feeds that are not RSS or Atom are read the same way as in a run.
+func parseFeed(feedURL, fixturesDir string) error {
+	src := source.For(feedURL)
+	if fixturesDir == "" {
+		_, err := src.Fetch(feedURL)
+		return err
+	}
+
+	file, err := os.Open(filepath.Join(fixturesDir, FixtureName(feedURL)))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if parser, ok := src.(source.Parser); ok {
+		_, err = parser.Parse(file)
+		return err
+	}
+	_, err = gofeed.NewParser().Parse(file)
+	return err
+}
+
+// PrintFeedTable prints the feeds grouped by kind, such as Medium tag or user feeds, with a count per kind.
+func PrintFeedTable(urls []string) {
+	groups := make(map[string][]string)
+	for _, u := range urls {
+		kind := source.Kind(u)
+		groups[kind] = append(groups[kind], u)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TYPE\tURL")
+	for _, kind := range source.Kinds() {
+		for _, u := range groups[kind] {
+			fmt.Fprintf(writer, "%s\t%s\n", kind, u)
+		}
+	}
+	writer.Flush()
+
+	fmt.Println()
+	for _, kind := range source.Kinds() {
+		fmt.Printf("%-18s %d\n", kind, len(groups[kind]))
+	}
+	fmt.Printf("%-18s %d\n", "total", len(urls))
+}
+
+// PrintFeedHealth prints the health of the feeds, leaving out healthy ones unless all is set.
+func PrintFeedHealth(feeds []db.FeedHealth, now time.Time, staleAfter time.Duration, all bool) {
+	quarantineAfter := db.QuarantineAfter()
+	counts := make(map[string]int)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "STATUS\tFAILURES\tLAST SUCCESS\tLAST ITEM\tLATENCY\tURL\tERROR")
+	for _, feed := range feeds {
+		status := feed.Status(now, quarantineAfter, staleAfter)
+		counts[status]++
+		if status == db.HealthOK && !all {
+			continue
+		}
+		if status == db.HealthQuarantined {
+			status += " until " + feed.QuarantinedUntil.Format(global.DateFormat)
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%dms\t%s\t%s\n", status, feed.ConsecutiveFailures,
+			formatDate(feed.LastSuccessAt), formatDate(feed.LastItemAt), feed.LastLatencyMs, feed.FeedURL, feed.LastError)
+	}
+	writer.Flush()
+
+	fmt.Println()
+	for _, status := range []string{db.HealthOK, db.HealthFailing, db.HealthQuarantined, db.HealthStale, db.HealthDead} {
+		fmt.Printf("%-12s %d\n", status, counts[status])
+	}
+}
+
+// formatDate formats an optional time as a date, or "-" when it is unset.
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(global.DateFormat)
+}
+
+// init registers the feeds subcommands.
+func init() {
+	feedsValidateCmd.Flags().StringVar(&global.FeedFixturesDir, "fixtures", "", "Directory of saved feed responses to parse instead of fetching live")
+
+	feedsHealthCmd.Flags().DurationVar(&global.HealthStaleAfter, "stale", 30*24*time.Hour, "Age of the newest item after which a feed is reported as stale")
+	feedsHealthCmd.Flags().BoolVar(&global.HealthAll, "all", false, "List healthy feeds as well")
+
+	feedsCmd.AddCommand(feedsListCmd, feedsAddCmd, feedsRemoveCmd, feedsValidateCmd, feedsHealthCmd)
+	rootCmd.AddCommand(feedsCmd)
+}
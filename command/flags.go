@@ -3,6 +3,7 @@ package command
 import (
 	log "github.com/sirupsen/logrus"
 	"writeup-finder.go/global"
+	"writeup-finder.go/telegram"
 )
 
 // ManageFlags validates and logs the parsed flags.
@@ -12,6 +13,10 @@ func ManageFlags() {
 	log.Infof("[+] Use Database: %v", global.UseDatabase)
 	log.Infof("[+] Send to Telegram: %v", global.SendToTelegramFlag)
 
+	if global.DryRun {
+		log.Info("[+] Dry run: nothing will be saved or sent.")
+	}
+
 	if global.ProxyURL != "" {
 		log.Infof("[+] Proxy URL: %v", global.ProxyURL)
 	} else {
@@ -21,11 +26,17 @@ func ManageFlags() {
 
 // ValidateFlags ensures that flag combinations are valid and throws errors for invalid input.
 func ValidateFlags() {
-	if !global.UseDatabase {
-		log.Fatal("You must specify --database to save articles in the database.")
+	if !global.UseDatabase && !global.DryRun {
+		log.Fatal("You must specify --database to save articles in the database, or --dry-run to preview them.")
 	}
 
 	if global.ProxyURL != "" && !global.SendToTelegramFlag {
 		log.Fatal("Error: --proxy option is only valid when used with --telegram.")
 	}
+
+	if global.ProxyURL != "" {
+		if err := telegram.ValidateProxyURL(global.ProxyURL); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
 }
@@ -0,0 +1,111 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"writeup-finder.go/config"
+	"writeup-finder.go/global"
+	"writeup-finder.go/telegram"
+	"writeup-finder.go/utils"
+)
+
+// keywordsCmd groups the subcommands that inspect keywords.json.
+var keywordsCmd = &cobra.Command{
+	Use:   "keywords",
+	Short: "Inspect the keyword rules used to pick a Telegram topic",
+}
+
+// keywordsExplainCmd shows which keyword patterns match a title and which one wins.
+var keywordsExplainCmd = &cobra.Command{
+	Use:   "explain [TITLE]",
+	Short: "Show which keyword rules match a title",
+	Long: `Explain prints every keyword pattern from keywords.json that matches the title, with its
+group, priority, thread variable, score and the field and text it matched, and marks the rule that decides the topic.
+With MAX_TOPICS_PER_ARTICLE above 1, the rules of the other topics the article would be posted to are marked ALSO.
+Rules that target the body are matched against --description, --content and --categories when given.
+Without an argument (or with "-") titles are read from stdin, one per line.
+No Telegram or database credentials are needed; thread IDs are shown when set in the environment.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		keywords, err := utils.LoadKeywordsWith(global.KeywordsFile, config.Lookup)
+		utils.HandleError(err, "Failed to load keyword patterns", true)
+
+		text := utils.ArticleText{
+			Description: global.ExplainDescription,
+			Content:     global.ExplainContent,
+			Categories:  global.ExplainCategories,
+		}
+
+		if len(args) == 1 && args[0] != "-" {
+			text.Title = args[0]
+			ExplainTitle(text, keywords)
+			return
+		}
+
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if text.Title = strings.TrimSpace(scanner.Text()); text.Title != "" {
+				ExplainTitle(text, keywords)
+			}
+		}
+		utils.HandleError(scanner.Err(), "Error reading titles from stdin", true)
+	},
+}
+
+// ExplainTitle prints the keyword matches for a single title and the other fields of the article.
+func ExplainTitle(text utils.ArticleText, keywords []utils.KeywordPattern) {
+	fmt.Println(color.HiWhiteString("Title: %s", text.Title))
+
+	matches := utils.ExplainArticle(text, keywords)
+	if len(matches) == 0 {
+		fmt.Println(color.YellowString("  No keyword matched, the article goes to MAIN_THREAD_ID"))
+		fmt.Println()
+		return
+	}
+
+	routed := make(map[*regexp.Regexp]bool)
+	for _, route := range utils.RouteMatches(matches, telegram.MaxTopics()) {
+		routed[route.Keyword.Pattern] = true
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "  \tGROUP\tPRIORITY\tTHREAD\tSCORE\tFIELD\tMATCHED")
+	for _, match := range matches {
+		marker := ""
+		switch {
+		case match.Winner:
+			marker = "WIN"
+		case routed[match.Keyword.Pattern]:
+			marker = "ALSO"
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%d\t%s\t%d\t%s\t%q\n", marker, match.Keyword.Group, match.Keyword.Priority,
+			describeThread(match.Keyword), match.Score, match.Field, match.Matched)
+	}
+	writer.Flush()
+	fmt.Println()
+}
+
+// describeThread renders the thread variable name with its resolved value when available.
+func describeThread(keyword utils.KeywordPattern) string {
+	if keyword.ThreadID == "" {
+		return keyword.ThreadEnv + " (unset)"
+	}
+	return fmt.Sprintf("%s (%s)", keyword.ThreadEnv, keyword.ThreadID)
+}
+
+// init registers the keywords subcommands.
+func init() {
+	keywordsExplainCmd.Flags().StringVar(&global.ExplainDescription, "description", "", "Description of the article")
+	keywordsExplainCmd.Flags().StringVar(&global.ExplainContent, "content", "", "Content of the article")
+	keywordsExplainCmd.Flags().StringSliceVar(&global.ExplainCategories, "categories", nil, "Categories of the article, comma separated")
+
+	keywordsCmd.AddCommand(keywordsExplainCmd)
+	rootCmd.AddCommand(keywordsCmd)
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Serve runs ManageAction immediately and then once per interval until SIGINT or SIGTERM is received.
+// A tick that fires while the previous pass is still running is skipped instead of queued.
+// On shutdown it stops scheduling new passes and waits for the running one to finish.
+func Serve(interval time.Duration) {
+	if interval <= 0 {
+		log.Fatal("The --interval value must be greater than zero.")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var running atomic.Bool
+	var wg sync.WaitGroup
+
+	runPass := func() {
+		if !running.CompareAndSwap(false, true) {
+			log.Warn("[!] Previous pass is still running, skipping this tick.")
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer running.Store(false)
+			ManageAction()
+		}()
+	}
+
+	log.Infof("[+] Serving with an interval of %v", interval)
+	runPass()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			runPass()
+		case <-ctx.Done():
+			// Restore default signal handling so a second signal terminates immediately
+			stop()
+			log.Info("[+] Shutdown signal received, waiting for the current pass to finish...")
+			wg.Wait()
+			log.Info("[+] Writeup Finder stopped.")
+			return
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"writeup-finder.go/db"
+	"writeup-finder.go/global"
+	"writeup-finder.go/utils"
+)
+
+// statsCmd prints statistics over the article archive.
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics over the archived articles",
+	Long: `Stats reports the number of articles per day, week and topic, the feeds and authors that
+produced the most articles, the premium versus free ratio and the YouTube versus written ratio.
+Use --since or --max-age to restrict the period and --json for machine-readable output.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var since time.Time
+		if global.Since != "" {
+			parsed, err := ParseTimeFlag(global.Since, false)
+			utils.HandleError(err, "Invalid --since value", true)
+			since = parsed
+		} else if global.MaxAge > 0 {
+			since = time.Now().Add(-global.MaxAge)
+		}
+
+		database := db.ConnectDB()
+		defer database.Close()
+
+		stats, err := db.CollectStats(database, since, global.StatsLimit)
+		utils.HandleError(err, "Error collecting statistics", true)
+
+		if global.StatsJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			utils.HandleError(encoder.Encode(stats), "Error encoding statistics", true)
+			return
+		}
+		PrintStats(stats)
+	},
+}
+
+// PrintStats prints the statistics as a set of tables.
+func PrintStats(stats *db.Stats) {
+	utils.PrintPretty("Writeup Finder Statistics", color.FgHiYellow, true)
+	fmt.Printf("Total articles: %d\n", stats.Total)
+	fmt.Printf("Premium / free: %d / %d (%s premium)\n", stats.Premium, stats.Free, percent(stats.Premium, stats.Total))
+	fmt.Printf("YouTube / written: %d / %d (%s videos)\n", stats.Videos, stats.Written, percent(stats.Videos, stats.Total))
+
+	printCounts("Articles per day", "DAY", stats.PerDay)
+	printCounts("Articles per week", "WEEK", stats.PerWeek)
+	printCounts("Articles per topic", "TOPIC", stats.PerTopic)
+	printCounts("Top feeds", "FEED", stats.TopFeeds)
+	printCounts("Top authors", "AUTHOR", stats.TopAuthors)
+}
+
+// printCounts prints one grouping as a two-column table.
+func printCounts(title, header string, counts []db.Count) {
+	fmt.Println()
+	fmt.Println(color.HiWhiteString(title))
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "  %s\tCOUNT\n", header)
+	for _, count := range counts {
+		fmt.Fprintf(writer, "  %s\t%d\n", count.Key, count.Count)
+	}
+	writer.Flush()
+}
+
+// percent formats part/total as a percentage.
+func percent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// init registers the stats flags.
+func init() {
+	statsCmd.Flags().BoolVar(&global.StatsJSON, "json", false, "Print the statistics as JSON")
+	statsCmd.Flags().IntVar(&global.StatsLimit, "limit", 10, "Maximum number of rows per table")
+
+	rootCmd.AddCommand(statsCmd)
+}
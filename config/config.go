@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the configuration file looked up when neither --config nor WRITEUP_FINDER_CONFIG is set.
+const DefaultPath = "config.yaml"
+
+// Config is the structure of the optional YAML configuration file.
+// Every value can be overridden by the environment variable of the same meaning, and flags override both.
+type Config struct {
+	Telegram      TelegramConfig `yaml:"telegram"`
+	Database      DatabaseConfig `yaml:"database"`
+	Files         FilesConfig    `yaml:"files"`
+	Topics        []Topic        `yaml:"topics"`
+	KeywordGroups []KeywordGroup `yaml:"keyword_groups"`
+	FieldWeights  map[string]int `yaml:"field_weights"`
+	Feeds         []string       `yaml:"feeds"`
+}
+
+// TelegramConfig holds the Bot API credentials and the default destination.
+type TelegramConfig struct {
+	BotToken        string `yaml:"bot_token"`
+	ChatID          string `yaml:"chat_id"`
+	MainThreadID    string `yaml:"main_thread_id"`
+	YoutubeThreadID string `yaml:"youtube_thread_id"`
+	Proxy           string `yaml:"proxy"`
+}
+
+// DatabaseConfig holds the PostgreSQL connection settings.
+type DatabaseConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Name     string `yaml:"name"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+}
+
+// FilesConfig holds the paths of the data files.
+type FilesConfig struct {
+	Urls     string `yaml:"urls"`
+	Keywords string `yaml:"keywords"`
+}
+
+// Topic is a Telegram forum topic that keyword rules can route articles to.
+// Keyword rules reference a topic either by its name or by its environment variable.
+type Topic struct {
+	Name     string     `yaml:"name"`
+	Env      string     `yaml:"env"`
+	ThreadID string     `yaml:"thread_id"`
+	ChatID   string     `yaml:"chat_id"`
+	CVE      *CVEFilter `yaml:"cve"`
+}
+
+// CVEFilter selects the CVEs of the NVD source that are posted to a topic.
+type CVEFilter struct {
+	Severities []string `yaml:"severities"` // CVSS severities such as CRITICAL or HIGH; empty accepts all
+	Products   []string `yaml:"products"`   // Watchlist of "vendor:product" or "product" names; empty accepts all
+	MinScore   float64  `yaml:"min_score"`
+}
+
+// KeywordGroup represents a group of keywords with a common name.
+// The topics of an exclusive group are never shared with other topics in multi-route mode.
+type KeywordGroup struct {
+	Name      string    `json:"name" yaml:"name"`
+	Exclusive bool      `json:"exclusive,omitempty" yaml:"exclusive"`
+	Keywords  []Keyword `json:"keywords" yaml:"keywords"`
+}
+
+// Keyword represents a keyword pattern and its associated thread ID and priority as loaded from JSON or YAML.
+// Fields lists the parts of the article the pattern is matched against; it defaults to the title.
+type Keyword struct {
+	Pattern  string   `json:"pattern" yaml:"pattern"`
+	ThreadID string   `json:"threadID" yaml:"threadID"`
+	Priority int      `json:"priority" yaml:"priority"`
+	Fields   []string `json:"fields,omitempty" yaml:"fields"`
+}
+
+// EnvName returns the environment variable that holds the topic's thread ID.
+// It defaults to the upper-cased name followed by _THREAD_ID.
+func (t Topic) EnvName() string {
+	if t.Env != "" {
+		return t.Env
+	}
+	return strings.ToUpper(strings.ReplaceAll(t.Name, "-", "_")) + "_THREAD_ID"
+}
+
+var current = &Config{}
+
+// Current returns the loaded configuration, or an empty one if no file was loaded.
+func Current() *Config {
+	return current
+}
+
+// Load reads the configuration file at path and makes it the current configuration.
+// References such as ${DB_PASSWORD} in the Telegram, database and topic settings are expanded from the environment
+// so secrets can stay out of the file; keyword patterns are left as written. A missing file is only an error when required is true.
+func Load(path string, required bool) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !required {
+			return nil
+		}
+		return err
+	}
+
+	cfg := &Config{}
+	if err := yaml.Unmarshal(content, cfg); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	cfg.expandEnv()
+
+	for _, topic := range cfg.Topics {
+		if topic.Name == "" && topic.Env == "" {
+			return fmt.Errorf("parsing %s: every topic needs a name or an env", path)
+		}
+	}
+
+	current = cfg
+	return nil
+}
+
+// Lookup returns the value of a setting, preferring the environment variable over the configuration file.
+// It returns an empty string when neither defines the key.
+func Lookup(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return current.values()[key]
+}
+
+// LookupOr behaves like Lookup but returns fallback when the key is not set anywhere.
+func LookupOr(key, fallback string) string {
+	if value := Lookup(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// TopicEnv resolves a topic reference given by name or by env to the env name stored with articles.
+// Unknown names follow the same NAME_THREAD_ID convention as Topic.EnvName.
+func TopicEnv(reference string) string {
+	for _, topic := range current.Topics {
+		if topic.Name == reference || topic.EnvName() == reference {
+			return topic.EnvName()
+		}
+	}
+	if strings.HasSuffix(reference, "_THREAD_ID") {
+		return reference
+	}
+	return Topic{Name: reference}.EnvName()
+}
+
+// TopicChatID returns the chat a topic posts to when it overrides CHAT_ID, or an empty string.
+func TopicChatID(envName string) string {
+	for _, topic := range current.Topics {
+		if topic.EnvName() == envName {
+			return topic.ChatID
+		}
+	}
+	return ""
+}
+
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv replaces the ${VAR} references of the credential and ID fields with the value of the environment variable.
+func (c *Config) expandEnv() {
+	expand := func(value *string) {
+		*value = envReference.ReplaceAllStringFunc(*value, func(reference string) string {
+			return os.Getenv(envReference.FindStringSubmatch(reference)[1])
+		})
+	}
+
+	for _, value := range []*string{
+		&c.Telegram.BotToken, &c.Telegram.ChatID, &c.Telegram.MainThreadID, &c.Telegram.YoutubeThreadID, &c.Telegram.Proxy,
+		&c.Database.Host, &c.Database.Port, &c.Database.Name, &c.Database.User, &c.Database.Password,
+	} {
+		expand(value)
+	}
+	for i := range c.Topics {
+		expand(&c.Topics[i].ThreadID)
+		expand(&c.Topics[i].ChatID)
+	}
+}
+
+// values flattens the configuration into the environment variable names it stands in for.
+func (c *Config) values() map[string]string {
+	values := map[string]string{
+		"TELEGRAM_BOT_TOKEN": c.Telegram.BotToken,
+		"CHAT_ID":            c.Telegram.ChatID,
+		"MAIN_THREAD_ID":     c.Telegram.MainThreadID,
+		"YOUTUBE_THREAD_ID":  c.Telegram.YoutubeThreadID,
+		"PROXY_URL":          c.Telegram.Proxy,
+		"DB_HOST":            c.Database.Host,
+		"DB_PORT":            c.Database.Port,
+		"DB_NAME":            c.Database.Name,
+		"DB_USER":            c.Database.User,
+		"DB_PASSWORD":        c.Database.Password,
+		"URL_FILE":           c.Files.Urls,
+		"KEYWORDS_FILE":      c.Files.Keywords,
+	}
+	for _, topic := range c.Topics {
+		values[topic.EnvName()] = topic.ThreadID
+	}
+	return values
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoadExample checks that the example config parses and that env variables take precedence over it.
+func TestLoadExample(t *testing.T) {
+	t.Setenv("DB_PASSWORD", "s3cret")
+	t.Setenv("RECON_THREAD_ID", "1234")
+
+	assert.NoError(t, Load("../config.example.yaml", true))
+	defer func() { current = &Config{} }()
+
+	assert.Equal(t, "-1001234567890", Lookup("CHAT_ID"))
+	assert.Equal(t, "2", Lookup("MAIN_THREAD_ID"))
+	assert.Equal(t, "s3cret", Current().Database.Password)
+	assert.Equal(t, "4329", Lookup("MONEY_THREAD_ID"))
+	assert.Equal(t, "1234", Lookup("RECON_THREAD_ID"))
+	assert.Equal(t, "9500", Lookup("CLOUD_THREAD_ID"))
+	assert.Equal(t, "fallback", LookupOr("SOME_UNSET_KEY", "fallback"))
+	assert.Len(t, Current().KeywordGroups, 1)
+}
+
+// TestLoadMissing checks that only a required config file must exist.
+func TestLoadMissing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	assert.NoError(t, Load(path, false))
+	assert.Error(t, Load(path, true))
+
+	assert.NoError(t, os.WriteFile(path, []byte("topics:\n  - thread_id: 1\n"), 0644))
+	assert.Error(t, Load(path, true))
+}
+
+// TestLoadKeepsPatterns checks that only ${VAR} references of credential and ID fields are expanded,
+// so $ in keyword patterns is kept.
+func TestLoadKeepsPatterns(t *testing.T) {
+	t.Setenv("BOT_TOKEN", "123:abc")
+	t.Setenv("HOME", "/root")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `telegram:
+  bot_token: ${BOT_TOKEN}
+topics:
+  - name: money
+    thread_id: ${UNSET_THREAD_ID}
+keyword_groups:
+  - name: money
+    keywords:
+      - pattern: '\$[0-9]+ bounty$'
+        threadID: money
+      - pattern: '${HOME}$$'
+        threadID: money
+`
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	assert.NoError(t, Load(path, true))
+	defer func() { current = &Config{} }()
+
+	assert.Equal(t, "123:abc", Current().Telegram.BotToken)
+	assert.Equal(t, "", Current().Topics[0].ThreadID)
+	assert.Equal(t, `\$[0-9]+ bounty$`, Current().KeywordGroups[0].Keywords[0].Pattern)
+	assert.Equal(t, "${HOME}$$", Current().KeywordGroups[0].Keywords[1].Pattern)
+}
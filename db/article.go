@@ -0,0 +1,82 @@
+package db
+
+import (
+	"time"
+)
+
+// Article is the record stored in the articles table for every processed feed item.
+type Article struct {
+	ID          int64
+	URL         string
+	Title       string
+	DedupeKey   string
+	GUID        string
+	FeedURL     string
+	SourceType  string
+	Author      string
+	Categories  []string
+	Topic       string
+	ThreadID    string
+	Premium     bool
+	MirrorURL   string
+	PublishedAt time.Time
+	FetchedAt   time.Time
+	SentAt      *time.Time
+	Simhash     uint64 // Fingerprint of the title and description, zero if not computed
+	MessageID   int64  // Telegram message the article was posted with, zero if it was not sent
+	Message     string
+	DuplicateOf *int64 // Article this one is a near duplicate of
+	CreatedAt   time.Time
+	Raw         []byte
+	Threads     []ArticleThread
+}
+
+// ArticleThread is a thread an article was routed to, stored in the article_threads table.
+// The main thread is recorded in the articles table as well.
+type ArticleThread struct {
+	Topic     string
+	ThreadID  string
+	MessageID int64 // Zero if the message was not sent
+	SentAt    *time.Time
+}
+
+// Link returns the URL shared in messages: the mirror for premium articles, the original otherwise.
+func (a *Article) Link() string {
+	if a.MirrorURL != "" {
+		return a.MirrorURL
+	}
+	return a.URL
+}
+
+// nullTime converts a zero time to NULL.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nullString converts an empty string to NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullInt converts zero to NULL.
+func nullInt(n int64) *int64 {
+	if n == 0 {
+		return nil
+	}
+	return &n
+}
+
+// nullJSON converts an empty JSON document to NULL.
+func nullJSON(raw []byte) *string {
+	if len(raw) == 0 {
+		return nil
+	}
+	s := string(raw)
+	return &s
+}
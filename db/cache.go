@@ -0,0 +1,35 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+
+	"writeup-finder.go/utils"
+)
+
+// FeedCache stores the conditional request validators of every feed in the feed_cache table.
+type FeedCache struct {
+	DB *sql.DB
+}
+
+// Load returns the cache entry of a feed and whether there is one.
+func (c FeedCache) Load(feedURL string) (utils.FeedCacheEntry, bool, error) {
+	var entry utils.FeedCacheEntry
+	err := c.DB.QueryRow("SELECT etag, last_modified, content_hash FROM feed_cache WHERE feed_url = $1", feedURL).
+		Scan(&entry.ETag, &entry.LastModified, &entry.ContentHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entry, false, nil
+	}
+	return entry, err == nil, err
+}
+
+// Store saves the cache entry of a feed, replacing the previous one.
+func (c FeedCache) Store(feedURL string, entry utils.FeedCacheEntry) error {
+	_, err := c.DB.Exec(`
+		INSERT INTO feed_cache (feed_url, etag, last_modified, content_hash, updated_at) VALUES ($1, $2, $3, $4, now())
+		ON CONFLICT (feed_url) DO UPDATE SET
+			etag = EXCLUDED.etag, last_modified = EXCLUDED.last_modified,
+			content_hash = EXCLUDED.content_hash, updated_at = EXCLUDED.updated_at
+	`, feedURL, entry.ETag, entry.LastModified, entry.ContentHash)
+	return err
+}
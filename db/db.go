@@ -3,37 +3,106 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"os"
+	"time"
 
-	_ "github.com/lib/pq" // Postgres driver
+	"github.com/lib/pq" // Postgres driver
 	"github.com/sirupsen/logrus"
+	"writeup-finder.go/config"
 	"writeup-finder.go/utils"
 )
 
-// ConnectDB establishes a connection to the PostgreSQL database using environment variables.
+// ConnectDB establishes a connection to the PostgreSQL database using environment variables or the config file.
 // It returns a pointer to the sql.DB object or logs a fatal error if the connection fails.
 func ConnectDB() *sql.DB {
-	connStr := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=require",
-		os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"),
-		os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"))
-
-	db, err := sql.Open("postgres", connStr)
+	db, err := OpenDB()
 	utils.HandleError(err, "Error in Connection to DB", true)
 
 	logrus.Info("[+] Database connection established.")
 	return db
 }
 
-// SaveUrlToDB inserts a URL and its corresponding title into the articles table.
+// OpenDB opens the PostgreSQL connection and pings it, so bad credentials are reported immediately.
+func OpenDB() (*sql.DB, error) {
+	connStr := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=require",
+		config.Lookup("DB_HOST"), config.Lookup("DB_PORT"), config.Lookup("DB_NAME"),
+		config.Lookup("DB_USER"), config.Lookup("DB_PASSWORD"))
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// SaveUrlToDB inserts an article and its metadata into the articles table, and its threads into article_threads.
+// An article whose dedupe key is already stored is ignored.
 // It logs an error if the operation fails but does not stop the program execution.
-func SaveUrlToDB(db *sql.DB, url, title string) {
-	_, err := db.Exec("INSERT INTO articles (url, title) VALUES ($1, $2)", url, title)
+func SaveUrlToDB(db *sql.DB, article *Article) {
+	err := db.QueryRow(`
+		INSERT INTO articles (
+			url, title, normalized_url, dedupe_key, guid, source, source_type, author, categories,
+			topic, thread_id, premium, mirror_url, published_at, fetched_at, sent_at, raw,
+			simhash, message_id, message, duplicate_of
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+		ON CONFLICT (dedupe_key) DO NOTHING
+		RETURNING id
+	`,
+		article.URL, article.Title, utils.CanonicalizeURL(article.URL), article.DedupeKey, nullString(article.GUID),
+		article.FeedURL, article.SourceType,
+		article.Author, pq.Array(article.Categories), article.Topic, article.ThreadID, article.Premium,
+		article.MirrorURL, nullTime(article.PublishedAt), nullTime(article.FetchedAt), article.SentAt,
+		nullJSON(article.Raw), nullInt(int64(article.Simhash)), nullInt(article.MessageID), nullString(article.Message),
+		article.DuplicateOf).Scan(&article.ID)
+	if err == sql.ErrNoRows {
+		return // Already stored
+	}
 	utils.HandleError(err, "Error saving URL and title to database", false)
+	if err != nil {
+		return
+	}
+
+	for _, thread := range article.Threads {
+		_, err := db.Exec(`
+			INSERT INTO article_threads (article_id, topic, thread_id, message_id, sent_at) VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (article_id, topic) DO NOTHING
+		`, article.ID, thread.Topic, thread.ThreadID, nullInt(thread.MessageID), thread.SentAt)
+		utils.HandleError(err, "Error saving article thread to database", false)
+	}
+}
+
+// ArticleExists reports whether an article with the given dedupe key, or the same GUID in the same feed, is already stored.
+// Matching the GUID as well catches an article whose link changed since it was sent, such as a renamed post.
+func ArticleExists(db *sql.DB, dedupeKey, guid, feedURL string) (bool, error) {
+	var exists bool
+	err := db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM articles WHERE dedupe_key = $1 OR ($2 <> '' AND source = $3 AND guid = $2))
+	`, dedupeKey, guid, feedURL).Scan(&exists)
+	return exists, err
+}
+
+// FirstSeen returns the time the item with the given dedupe key was first seen, recording now if it is new.
+// It stands in for the publication date of items whose feed gives none.
+func FirstSeen(db *sql.DB, dedupeKey, feedURL string, now time.Time) (time.Time, error) {
+	var firstSeen time.Time
+	// The no-op update makes RETURNING yield the stored row when the key already exists
+	err := db.QueryRow(`
+		INSERT INTO feed_items_seen (dedupe_key, feed_url, first_seen_at) VALUES ($1, $2, $3)
+		ON CONFLICT (dedupe_key) DO UPDATE SET dedupe_key = EXCLUDED.dedupe_key
+		RETURNING first_seen_at
+	`, dedupeKey, feedURL, now).Scan(&firstSeen)
+	return firstSeen, err
 }
 
 // CreateArticlesTable creates the articles table if it does not already exist.
 // The table includes columns for id (primary key), url, and title.
 // It logs a fatal error if the table creation fails.
+//
+// Deprecated: the schema is managed by MigrateUp, whose first migration creates the same table.
 func CreateArticlesTable(db *sql.DB) {
 	_, err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS articles (
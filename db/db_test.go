@@ -1,7 +1,9 @@
 package db
 
 import (
+	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -52,14 +54,161 @@ func TestSaveUrlToDB(t *testing.T) {
 	assert.NoError(t, err)
 	defer db.Close()
 
-	// Mock the Exec method to simulate inserting a URL and title
-	mock.ExpectExec("INSERT INTO articles").
-		WithArgs("https://example.com", "Example Title").
+	article := &Article{
+		URL:         "https://example.com/post?utm_source=rss",
+		Title:       "Example Title",
+		DedupeKey:   "url:https://example.com/post",
+		GUID:        "https://medium.com/p/4f2c9a1b7d3e",
+		FeedURL:     "https://medium.com/feed/tag/xss",
+		SourceType:  "medium",
+		Author:      "Jane",
+		Categories:  []string{"xss"},
+		Topic:       "MAIN_THREAD_ID",
+		ThreadID:    "2",
+		PublishedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Threads:     []ArticleThread{{Topic: "MAIN_THREAD_ID", ThreadID: "2"}},
+	}
+
+	// Mock the insert of the article and of the thread it was routed to
+	mock.ExpectQuery("INSERT INTO articles").
+		WithArgs("https://example.com/post?utm_source=rss", "Example Title", "https://example.com/post", "url:https://example.com/post",
+			"https://medium.com/p/4f2c9a1b7d3e", "https://medium.com/feed/tag/xss", "medium", "Jane", sqlmock.AnyArg(), "MAIN_THREAD_ID", "2",
+			false, "", sqlmock.AnyArg(), nil, nil, nil, nil, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectExec("INSERT INTO article_threads").
+		WithArgs(int64(7), "MAIN_THREAD_ID", "2", nil, nil).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Call the SaveUrlToDB function
-	SaveUrlToDB(db, "https://example.com", "Example Title")
+	SaveUrlToDB(db, article)
 
 	// Ensure all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// TestFirstSeen checks that the stored first-seen time is returned rather than the current one.
+func TestFirstSeen(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2025, 2, 5, 8, 0, 0, 0, time.UTC)
+	stored := time.Date(2025, 2, 4, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("INSERT INTO feed_items_seen").
+		WithArgs("title:Undated", "https://example.com/feed.json", now).
+		WillReturnRows(sqlmock.NewRows([]string{"first_seen_at"}).AddRow(stored))
+
+	firstSeen, err := FirstSeen(db, "title:Undated", "https://example.com/feed.json", now)
+	assert.NoError(t, err)
+	assert.Equal(t, stored, firstSeen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestQuarantineUntil checks that the quarantine starts after the configured failures and doubles up to the maximum.
+func TestQuarantineUntil(t *testing.T) {
+	now := time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, QuarantineUntil(now, 4, 5).IsZero())
+	assert.Equal(t, now.Add(QuarantineBase), QuarantineUntil(now, 5, 5))
+	assert.Equal(t, now.Add(2*QuarantineBase), QuarantineUntil(now, 6, 5))
+	assert.Equal(t, now.Add(QuarantineMax), QuarantineUntil(now, 50, 5))
+}
+
+// TestRecordFeedCheckPrunes checks that checks of the feed older than the retention are deleted.
+func TestRecordFeedCheckPrunes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC)
+	feedURL := "https://example.com/feed"
+	mock.ExpectExec("INSERT INTO feed_checks").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("DELETE FROM feed_checks WHERE feed_url = \\$1 AND checked_at < \\$2").
+		WithArgs(feedURL, now.AddDate(0, 0, -30)).WillReturnResult(sqlmock.NewResult(0, 12))
+	mock.ExpectQuery("SELECT consecutive_failures FROM feed_health").WithArgs(feedURL).WillReturnError(sql.ErrNoRows)
+	mock.ExpectExec("INSERT INTO feed_health").WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, RecordFeedCheck(db, FeedCheck{FeedURL: feedURL, CheckedAt: now}, 5, 30))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestFeedHealthStatus checks how feeds are classified in the health report.
+func TestFeedHealthStatus(t *testing.T) {
+	now := time.Date(2025, 2, 5, 0, 0, 0, 0, time.UTC)
+	stale := 30 * 24 * time.Hour
+	recent := now.Add(-time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+	later := now.Add(time.Hour)
+
+	assert.Equal(t, HealthOK, FeedHealth{LastSuccessAt: &recent, LastItemAt: &recent}.Status(now, 5, stale))
+	assert.Equal(t, HealthStale, FeedHealth{LastSuccessAt: &recent, LastItemAt: &old}.Status(now, 5, stale))
+	assert.Equal(t, HealthFailing, FeedHealth{ConsecutiveFailures: 2, LastSuccessAt: &recent}.Status(now, 5, stale))
+	assert.Equal(t, HealthQuarantined, FeedHealth{ConsecutiveFailures: 5, LastSuccessAt: &recent, QuarantinedUntil: &later}.Status(now, 5, stale))
+	assert.Equal(t, HealthDead, FeedHealth{ConsecutiveFailures: 9, LastSuccessAt: &old, QuarantinedUntil: &later}.Status(now, 5, stale))
+}
+
+// TestFindNearDuplicate checks that the closest article within the distance is returned.
+func TestFindNearDuplicate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	since := time.Date(2025, 1, 29, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "url", "title", "topic", "simhash", "message_id", "message"}).
+		AddRow(1, "https://far.example.com", "Far", "MAIN_THREAD_ID", int64(0xFF), nil, nil).
+		AddRow(2, "https://near.example.com", "Near", "MAIN_THREAD_ID", int64(0b11), 42, "Near message")
+	mock.ExpectQuery(`SELECT id, url, title, topic, simhash, message_id, message FROM articles .+ \(\(\(simhash >> 0\) & 65535\) = \$2 OR`).
+		WithArgs(since, int64(1), int64(0), int64(0), int64(0)).WillReturnRows(rows)
+
+	original, err := FindNearDuplicate(db, 0b1, since, 3)
+	assert.NoError(t, err)
+	if assert.NotNil(t, original) {
+		assert.Equal(t, int64(2), original.ID)
+		assert.Equal(t, int64(42), original.MessageID)
+		assert.Equal(t, "Near message", original.Message)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSentThreads checks that every thread a message was sent to is returned.
+func TestSentThreads(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sentAt := time.Date(2025, 2, 4, 9, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"topic", "thread_id", "message_id", "sent_at"}).
+		AddRow("XSS_THREAD_ID", "4321", 42, sentAt).
+		AddRow("MONEY_THREAD_ID", nil, 43, nil)
+	mock.ExpectQuery("SELECT topic, thread_id, message_id, sent_at FROM article_threads").
+		WithArgs(int64(7)).WillReturnRows(rows)
+
+	threads, err := SentThreads(db, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []ArticleThread{
+		{Topic: "XSS_THREAD_ID", ThreadID: "4321", MessageID: 42, SentAt: &sentAt},
+		{Topic: "MONEY_THREAD_ID", MessageID: 43},
+	}, threads)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestSimhashBands checks that fingerprints within the distance always share a band.
+func TestSimhashBands(t *testing.T) {
+	hash := uint64(0xF0F0_1234_ABCD_0F0F)
+	near := hash ^ 0x8001_0000_0100_0011 // 5 bits apart
+
+	_, hashBands := simhashBands(hash, 8)
+	_, nearBands := simhashBands(near, 8)
+	shared := 0
+	for i := range hashBands {
+		if hashBands[i] == nearBands[i] {
+			shared++
+		}
+	}
+	assert.Len(t, hashBands, 9)
+	assert.GreaterOrEqual(t, shared, 1)
+
+	condition, args := simhashBands(hash, 0)
+	assert.Equal(t, "simhash = $2", condition)
+	assert.Equal(t, []interface{}{int64(hash)}, args)
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"writeup-finder.go/config"
+	"writeup-finder.go/utils"
+)
+
+// DefaultNearDuplicateDays is how far back articles are compared for near duplicates,
+// unless NEAR_DUPLICATE_DAYS says otherwise.
+const DefaultNearDuplicateDays = 7
+
+// DefaultNearDuplicateDistance is the largest number of differing fingerprint bits between near duplicates,
+// unless NEAR_DUPLICATE_DISTANCE says otherwise.
+const DefaultNearDuplicateDistance = 8
+
+// Near duplicate modes selected with NEAR_DUPLICATE_MODE.
+const (
+	NearDuplicateSuppress = "suppress" // The copy is recorded but not posted
+	NearDuplicateAlsoOn   = "also-on"  // The copy is added to the original message as an "Also on" link
+)
+
+// NearDuplicateDays returns the number of days from NEAR_DUPLICATE_DAYS, or DefaultNearDuplicateDays
+// when it is unset or invalid. Zero turns near duplicate detection off.
+func NearDuplicateDays() int {
+	if days, err := strconv.Atoi(config.Lookup("NEAR_DUPLICATE_DAYS")); err == nil && days >= 0 {
+		return days
+	}
+	return DefaultNearDuplicateDays
+}
+
+// NearDuplicateDistance returns the distance from NEAR_DUPLICATE_DISTANCE, or DefaultNearDuplicateDistance
+// when it is unset or invalid.
+func NearDuplicateDistance() int {
+	if distance, err := strconv.Atoi(config.Lookup("NEAR_DUPLICATE_DISTANCE")); err == nil && distance >= 0 {
+		return distance
+	}
+	return DefaultNearDuplicateDistance
+}
+
+// NearDuplicateMode returns the mode from NEAR_DUPLICATE_MODE, NearDuplicateAlsoOn by default.
+func NearDuplicateMode() string {
+	if config.Lookup("NEAR_DUPLICATE_MODE") == NearDuplicateSuppress {
+		return NearDuplicateSuppress
+	}
+	return NearDuplicateAlsoOn
+}
+
+// simhashBands returns a condition selecting the fingerprints that may be within maxDistance bits of simhash,
+// with its arguments numbered from $2. The fingerprint is cut into maxDistance+1 bands: fingerprints differing
+// in at most maxDistance bits leave at least one band untouched, so only those sharing a band are compared.
+func simhashBands(simhash uint64, maxDistance int) (string, []interface{}) {
+	bands := maxDistance + 1
+	if bands > 64 {
+		return "TRUE", nil
+	}
+	if bands == 1 {
+		return "simhash = $2", []interface{}{int64(simhash)}
+	}
+
+	conditions := make([]string, bands)
+	args := make([]interface{}, bands)
+	for i := 0; i < bands; i++ {
+		start, end := i*64/bands, (i+1)*64/bands
+		mask := uint64(1)<<(end-start) - 1
+		conditions[i] = fmt.Sprintf("((simhash >> %d) & %d) = $%d", start, mask, i+2)
+		args[i] = int64(simhash >> start & mask)
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args
+}
+
+// FindNearDuplicate returns the closest original article stored since the given time whose fingerprint differs
+// from simhash by at most maxDistance bits, or nil if there is none. Copies of other articles are not considered,
+// so every copy points at the first article posted. Only the articles sharing a band of the fingerprint are loaded.
+func FindNearDuplicate(db *sql.DB, simhash uint64, since time.Time, maxDistance int) (*Article, error) {
+	bands, args := simhashBands(simhash, maxDistance)
+	rows, err := db.Query(`
+		SELECT id, url, title, topic, simhash, message_id, message
+		FROM articles
+		WHERE simhash IS NOT NULL AND duplicate_of IS NULL AND created_at >= $1 AND `+bands,
+		append([]interface{}{since}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var closest *Article
+	closestDistance := maxDistance + 1
+	for rows.Next() {
+		var (
+			article   Article
+			topic     sql.NullString
+			stored    int64
+			messageID sql.NullInt64
+			message   sql.NullString
+		)
+		if err := rows.Scan(&article.ID, &article.URL, &article.Title, &topic, &stored, &messageID, &message); err != nil {
+			return nil, err
+		}
+
+		if distance := utils.HammingDistance(simhash, uint64(stored)); distance < closestDistance {
+			article.Topic, article.Simhash, article.MessageID, article.Message = topic.String, uint64(stored), messageID.Int64, message.String
+			closest, closestDistance = &article, distance
+		}
+	}
+	return closest, rows.Err()
+}
+
+// UpdateMessage stores the new text of the message an article was posted with.
+func UpdateMessage(db *sql.DB, id int64, message string) error {
+	_, err := db.Exec("UPDATE articles SET message = $2 WHERE id = $1", id, message)
+	return err
+}
+
+// SentThreads returns the threads a message of the article was sent to, as recorded in article_threads.
+func SentThreads(db *sql.DB, articleID int64) ([]ArticleThread, error) {
+	rows, err := db.Query(`
+		SELECT topic, thread_id, message_id, sent_at
+		FROM article_threads
+		WHERE article_id = $1 AND message_id IS NOT NULL
+		ORDER BY sent_at
+	`, articleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var threads []ArticleThread
+	for rows.Next() {
+		var (
+			thread   ArticleThread
+			threadID sql.NullString
+			sentAt   sql.NullTime
+		)
+		if err := rows.Scan(&thread.Topic, &threadID, &thread.MessageID, &sentAt); err != nil {
+			return nil, err
+		}
+		thread.ThreadID = threadID.String
+		if sentAt.Valid {
+			thread.SentAt = &sentAt.Time
+		}
+		threads = append(threads, thread)
+	}
+	return threads, rows.Err()
+}
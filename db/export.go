@@ -0,0 +1,54 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ArticleFilter narrows the articles returned by StreamArticles. Zero values disable a filter.
+type ArticleFilter struct {
+	Topic string // Any topic the article was posted to, not only its main one
+	Since time.Time
+}
+
+// StreamArticles calls fn for every stored article matching the filter, oldest first.
+// Rows are read one at a time so large tables are never loaded into memory at once.
+// The raw feed item and near-duplicates of other articles are not loaded.
+func StreamArticles(db *sql.DB, filter ArticleFilter, fn func(*Article) error) error {
+	rows, err := db.Query(`
+		SELECT id, COALESCE(url, ''), COALESCE(title, ''), COALESCE(source, ''), COALESCE(source_type, ''),
+			COALESCE(author, ''), categories, COALESCE(topic, ''), COALESCE(thread_id, ''), premium,
+			COALESCE(mirror_url, ''), published_at, fetched_at, sent_at, created_at
+		FROM articles
+		WHERE duplicate_of IS NULL
+			AND ($1 = '' OR EXISTS (
+				SELECT 1 FROM article_threads WHERE article_threads.article_id = articles.id AND article_threads.topic = $1
+			))
+			AND ($2::timestamptz IS NULL OR COALESCE(published_at, created_at) >= $2)
+		ORDER BY id
+	`, filter.Topic, nullTime(filter.Since))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var article Article
+		var publishedAt, fetchedAt sql.NullTime
+		err := rows.Scan(&article.ID, &article.URL, &article.Title, &article.FeedURL, &article.SourceType,
+			&article.Author, pq.Array(&article.Categories), &article.Topic, &article.ThreadID, &article.Premium,
+			&article.MirrorURL, &publishedAt, &fetchedAt, &article.SentAt, &article.CreatedAt)
+		if err != nil {
+			return err
+		}
+		article.PublishedAt = publishedAt.Time
+		article.FetchedAt = fetchedAt.Time
+
+		if err := fn(&article); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
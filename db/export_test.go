@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStreamArticles checks that the topic is matched against every thread of an article, near-duplicates
+// are left out and every row is handed to the callback.
+func TestStreamArticles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 2, 4, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "url", "title", "source", "source_type", "author", "categories", "topic", "thread_id",
+		"premium", "mirror_url", "published_at", "fetched_at", "sent_at", "created_at"}
+	mock.ExpectQuery("FROM articles WHERE duplicate_of IS NULL AND .+ EXISTS \\( SELECT 1 FROM article_threads").
+		WithArgs("XSS_THREAD_ID", since).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(1, "https://example.com/xss", "XSS writeup", "https://example.com/feed", "rss", "Jane Doe",
+				"{xss,web}", "XSS_THREAD_ID", "42", false, "", created, created, nil, created))
+
+	var articles []*Article
+	err = StreamArticles(db, ArticleFilter{Topic: "XSS_THREAD_ID", Since: since}, func(article *Article) error {
+		articles = append(articles, article)
+		return nil
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, articles, 1) {
+		assert.Equal(t, "XSS writeup", articles[0].Title)
+		assert.Equal(t, []string{"xss", "web"}, articles[0].Categories)
+		assert.Equal(t, created, articles[0].PublishedAt)
+		assert.Nil(t, articles[0].SentAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
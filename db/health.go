@@ -0,0 +1,208 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"writeup-finder.go/config"
+)
+
+// DefaultQuarantineAfter is the number of failures in a row after which a feed is quarantined,
+// unless FEED_QUARANTINE_AFTER says otherwise.
+const DefaultQuarantineAfter = 5
+
+// DefaultCheckRetentionDays is how long the history of fetches is kept, unless FEED_CHECK_RETENTION_DAYS says otherwise.
+const DefaultCheckRetentionDays = 30
+
+// QuarantineBase is how long a feed is skipped when it is first quarantined.
+// Every further failure doubles it, up to QuarantineMax.
+const QuarantineBase = 6 * time.Hour
+
+// QuarantineMax is the longest a feed is skipped before it is checked again.
+const QuarantineMax = 7 * 24 * time.Hour
+
+// Feed health statuses reported by FeedHealth.Status.
+const (
+	HealthOK          = "ok"
+	HealthFailing     = "failing"
+	HealthQuarantined = "quarantined"
+	HealthDead        = "dead"
+	HealthStale       = "stale"
+)
+
+// FeedCheck is the outcome of one fetch of a feed.
+type FeedCheck struct {
+	FeedURL    string
+	CheckedAt  time.Time
+	Err        error
+	Latency    time.Duration
+	Items      int
+	LastItemAt time.Time // Newest item date, zero if unknown or the feed was unchanged
+}
+
+// FeedHealth is the current health of a feed.
+type FeedHealth struct {
+	FeedURL             string     `json:"feed_url"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error"`
+	LastCheckedAt       *time.Time `json:"last_checked_at"`
+	LastSuccessAt       *time.Time `json:"last_success_at"`
+	LastLatencyMs       int        `json:"last_latency_ms"`
+	LastItemAt          *time.Time `json:"last_item_at"`
+	QuarantinedUntil    *time.Time `json:"quarantined_until"`
+}
+
+// Status classifies the feed. A feed is dead when it has failed at least quarantineAfter times in a row
+// and has not worked for staleAfter, quarantined while it is skipped, failing after any failure,
+// and stale when it works but its newest item is older than staleAfter.
+func (h FeedHealth) Status(now time.Time, quarantineAfter int, staleAfter time.Duration) string {
+	switch {
+	case h.ConsecutiveFailures >= quarantineAfter && (h.LastSuccessAt == nil || now.Sub(*h.LastSuccessAt) > staleAfter):
+		return HealthDead
+	case h.QuarantinedUntil != nil && h.QuarantinedUntil.After(now):
+		return HealthQuarantined
+	case h.ConsecutiveFailures > 0:
+		return HealthFailing
+	case h.LastItemAt != nil && now.Sub(*h.LastItemAt) > staleAfter:
+		return HealthStale
+	default:
+		return HealthOK
+	}
+}
+
+// QuarantineAfter returns the number of failures in a row after which a feed is quarantined.
+func QuarantineAfter() int {
+	if after, err := strconv.Atoi(config.Lookup("FEED_QUARANTINE_AFTER")); err == nil && after > 0 {
+		return after
+	}
+	return DefaultQuarantineAfter
+}
+
+// CheckRetentionDays returns the number of days from FEED_CHECK_RETENTION_DAYS, or DefaultCheckRetentionDays
+// when it is unset or invalid. Zero keeps the whole history.
+func CheckRetentionDays() int {
+	if days, err := strconv.Atoi(config.Lookup("FEED_CHECK_RETENTION_DAYS")); err == nil && days >= 0 {
+		return days
+	}
+	return DefaultCheckRetentionDays
+}
+
+// QuarantineUntil returns when a feed that failed the given number of times in a row is checked again,
+// or the zero time if it is not quarantined yet.
+func QuarantineUntil(now time.Time, failures, quarantineAfter int) time.Time {
+	if failures < quarantineAfter {
+		return time.Time{}
+	}
+
+	delay := QuarantineBase
+	for i := quarantineAfter; i < failures && delay < QuarantineMax; i++ {
+		delay *= 2
+	}
+	if delay > QuarantineMax {
+		delay = QuarantineMax
+	}
+	return now.Add(delay)
+}
+
+// RecordFeedCheck stores a check in the history and updates the health of the feed,
+// quarantining it once it has failed quarantineAfter times in a row. Checks of the feed older than
+// retentionDays are pruned so the history does not grow forever; zero keeps them all.
+func RecordFeedCheck(db *sql.DB, check FeedCheck, quarantineAfter, retentionDays int) error {
+	errText := ""
+	if check.Err != nil {
+		errText = check.Err.Error()
+	}
+	latency := int(check.Latency.Milliseconds())
+
+	_, err := db.Exec(`
+		INSERT INTO feed_checks (feed_url, checked_at, ok, error, latency_ms, items) VALUES ($1, $2, $3, $4, $5, $6)
+	`, check.FeedURL, check.CheckedAt, check.Err == nil, errText, latency, check.Items)
+	if err != nil {
+		return err
+	}
+
+	if retentionDays > 0 {
+		_, err = db.Exec("DELETE FROM feed_checks WHERE feed_url = $1 AND checked_at < $2",
+			check.FeedURL, check.CheckedAt.AddDate(0, 0, -retentionDays))
+		if err != nil {
+			return err
+		}
+	}
+
+	var failures int
+	err = db.QueryRow("SELECT consecutive_failures FROM feed_health WHERE feed_url = $1", check.FeedURL).Scan(&failures)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	var lastSuccess, quarantinedUntil time.Time
+	if check.Err != nil {
+		failures++
+		quarantinedUntil = QuarantineUntil(check.CheckedAt, failures, quarantineAfter)
+	} else {
+		failures = 0
+		lastSuccess = check.CheckedAt
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO feed_health (
+			feed_url, consecutive_failures, last_error, last_checked_at, last_success_at,
+			last_latency_ms, last_item_at, quarantined_until
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (feed_url) DO UPDATE SET
+			consecutive_failures = EXCLUDED.consecutive_failures,
+			last_error = EXCLUDED.last_error,
+			last_checked_at = EXCLUDED.last_checked_at,
+			last_success_at = COALESCE(EXCLUDED.last_success_at, feed_health.last_success_at),
+			last_latency_ms = EXCLUDED.last_latency_ms,
+			last_item_at = COALESCE(EXCLUDED.last_item_at, feed_health.last_item_at),
+			quarantined_until = EXCLUDED.quarantined_until
+	`, check.FeedURL, failures, errText, check.CheckedAt, nullTime(lastSuccess), latency,
+		nullTime(check.LastItemAt), nullTime(quarantinedUntil))
+	return err
+}
+
+// QuarantinedFeeds returns the feeds that are skipped at the given time, with the time they are checked again.
+func QuarantinedFeeds(db *sql.DB, now time.Time) (map[string]time.Time, error) {
+	rows, err := db.Query("SELECT feed_url, quarantined_until FROM feed_health WHERE quarantined_until > $1", now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	feeds := make(map[string]time.Time)
+	for rows.Next() {
+		var feedURL string
+		var until time.Time
+		if err := rows.Scan(&feedURL, &until); err != nil {
+			return nil, err
+		}
+		feeds[feedURL] = until
+	}
+	return feeds, rows.Err()
+}
+
+// ListFeedHealth returns the health of every feed that has been checked, ordered by URL.
+func ListFeedHealth(db *sql.DB) ([]FeedHealth, error) {
+	rows, err := db.Query(`
+		SELECT feed_url, consecutive_failures, last_error, last_checked_at, last_success_at,
+			last_latency_ms, last_item_at, quarantined_until
+		FROM feed_health ORDER BY feed_url
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var feeds []FeedHealth
+	for rows.Next() {
+		var h FeedHealth
+		if err := rows.Scan(&h.FeedURL, &h.ConsecutiveFailures, &h.LastError, &h.LastCheckedAt, &h.LastSuccessAt,
+			&h.LastLatencyMs, &h.LastItemAt, &h.QuarantinedUntil); err != nil {
+			return nil, err
+		}
+		feeds = append(feeds, h)
+	}
+	return feeds, rows.Err()
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is one versioned schema change with its up and down SQL.
+// Step, when set, runs after the up SQL in the same transaction.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	Step    func(tx *sql.Tx) error
+}
+
+// MigrationState reports whether a migration has been applied and when.
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// LoadMigrations reads the embedded migration files, named NNNN_name.up.sql and NNNN_name.down.sql,
+// and returns them sorted by version. Every version must provide both directions.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		versionText, label, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionText)
+		if !ok || !found || err != nil || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name: %s", name)
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", name))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: label, Step: migrationSteps[version]}
+			byVersion[version] = migration
+		}
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s is missing its up or down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// appliedVersions creates the schema_version table if needed and returns the applied versions.
+func appliedVersions(db *sql.DB) (map[int]time.Time, error) {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		);
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("creating schema_version table: %w", err)
+	}
+
+	rows, err := db.Query("SELECT version, applied_at FROM schema_version")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
+
+// runMigration executes one direction of a migration and records it in schema_version, in a single transaction.
+func runMigration(db *sql.DB, migration Migration, up bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	script, record, args := migration.Down, "DELETE FROM schema_version WHERE version = $1", []any{migration.Version}
+	if up {
+		script, record, args = migration.Up, "INSERT INTO schema_version (version, name) VALUES ($1, $2)", []any{migration.Version, migration.Name}
+	}
+
+	if _, err := tx.Exec(script); err != nil {
+		return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+	}
+	if up && migration.Step != nil {
+		if err := migration.Step(tx); err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	if _, err := tx.Exec(record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MigrateUp applies every pending migration in order and returns how many were applied.
+func MigrateUp(db *sql.DB) (int, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		if err := runMigration(db, migration, true); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations and returns how many were reverted.
+func MigrateDown(db *sql.DB, steps int) (int, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return 0, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(migrations) - 1; i >= 0 && count < steps; i-- {
+		if _, ok := applied[migrations[i].Version]; !ok {
+			continue
+		}
+		if err := runMigration(db, migrations[i], false); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// MigrationStatus returns every known migration with the time it was applied, if it was.
+func MigrationStatus(db *sql.DB) ([]MigrationState, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, 0, len(migrations))
+	for _, migration := range migrations {
+		state := MigrationState{Migration: migration}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			state.AppliedAt = &appliedAt
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
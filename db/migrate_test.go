@@ -0,0 +1,86 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoadMigrations checks that the embedded migrations are complete and ordered by version.
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "migration versions must be contiguous")
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+	}
+}
+
+// TestMigrateUp checks that only the pending migrations are applied, each in its own transaction.
+func TestMigrateUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrations, err := LoadMigrations()
+	assert.NoError(t, err)
+
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_version").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_version").
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+
+	for _, migration := range migrations[1:] {
+		mock.ExpectBegin()
+		mock.ExpectExec(".+").WillReturnResult(sqlmock.NewResult(0, 0))
+		if migration.Step != nil {
+			mock.ExpectQuery("SELECT id, url FROM articles").WillReturnRows(sqlmock.NewRows([]string{"id", "url"}))
+			mock.ExpectExec("CREATE UNIQUE INDEX").WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+		mock.ExpectExec("INSERT INTO schema_version").
+			WithArgs(migration.Version, migration.Name).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+	}
+
+	applied, err := MigrateUp(db)
+	assert.NoError(t, err)
+	assert.Equal(t, len(migrations)-1, applied)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestRekeyArticles checks that stored articles get the keys of utils.CanonicalizeURL
+// and that a duplicate is kept without a key instead of being deleted.
+func TestRekeyArticles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id, url FROM articles").WillReturnRows(sqlmock.NewRows([]string{"id", "url"}).
+		AddRow(1, "https://medium.com/@jane/my-first-bug-bounty-4f2c9a1b7d3e?source=rss").
+		AddRow(2, "https://blog.example.com/?p=123").
+		AddRow(3, "https://jane.medium.com/my-first-bug-bounty-4f2c9a1b7d3e"))
+	mock.ExpectExec("UPDATE articles").
+		WithArgs(int64(1), "https://medium.com/p/4f2c9a1b7d3e", "url:https://medium.com/p/4f2c9a1b7d3e").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE articles").
+		WithArgs(int64(2), "https://blog.example.com/?p=123", "url:https://blog.example.com/?p=123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE articles").
+		WithArgs(int64(3), "https://medium.com/p/4f2c9a1b7d3e", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("CREATE UNIQUE INDEX IF NOT EXISTS articles_dedupe_key_key").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tx, err := db.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, rekeyArticles(tx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
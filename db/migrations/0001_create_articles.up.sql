@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS articles (
+	id SERIAL PRIMARY KEY,
+	url VARCHAR(1000),
+	title VARCHAR(1000)
+);
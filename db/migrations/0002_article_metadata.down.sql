@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS articles_created_at_idx;
+DROP INDEX IF EXISTS articles_title_idx;
+DROP INDEX IF EXISTS articles_normalized_url_key;
+
+ALTER TABLE articles
+	DROP COLUMN IF EXISTS normalized_url,
+	DROP COLUMN IF EXISTS topic,
+	DROP COLUMN IF EXISTS source,
+	DROP COLUMN IF EXISTS created_at;
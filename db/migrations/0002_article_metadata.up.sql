@@ -0,0 +1,21 @@
+ALTER TABLE articles
+	ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	ADD COLUMN IF NOT EXISTS source TEXT,
+	ADD COLUMN IF NOT EXISTS topic TEXT,
+	ADD COLUMN IF NOT EXISTS normalized_url TEXT;
+
+-- Same rule as db.NormalizeURL: drop the query string and fragment, except for YouTube watch links
+UPDATE articles SET normalized_url = CASE
+	WHEN url LIKE '%youtube.com/watch?%' THEN url
+	ELSE regexp_replace(url, '[?#].*$', '')
+END
+WHERE normalized_url IS NULL;
+
+-- Keep the oldest row of every duplicate so the unique index can be built
+DELETE FROM articles a
+USING articles b
+WHERE a.normalized_url = b.normalized_url AND a.id > b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS articles_normalized_url_key ON articles (normalized_url);
+CREATE INDEX IF NOT EXISTS articles_title_idx ON articles (title);
+CREATE INDEX IF NOT EXISTS articles_created_at_idx ON articles (created_at);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS articles_source_idx;
+DROP INDEX IF EXISTS articles_topic_sent_at_idx;
+
+ALTER TABLE articles
+	DROP COLUMN IF EXISTS raw,
+	DROP COLUMN IF EXISTS mirror_url,
+	DROP COLUMN IF EXISTS premium,
+	DROP COLUMN IF EXISTS thread_id,
+	DROP COLUMN IF EXISTS categories,
+	DROP COLUMN IF EXISTS author,
+	DROP COLUMN IF EXISTS source_type,
+	DROP COLUMN IF EXISTS sent_at,
+	DROP COLUMN IF EXISTS fetched_at,
+	DROP COLUMN IF EXISTS published_at;
@@ -0,0 +1,14 @@
+ALTER TABLE articles
+	ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS fetched_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS sent_at TIMESTAMPTZ,
+	ADD COLUMN IF NOT EXISTS source_type TEXT,
+	ADD COLUMN IF NOT EXISTS author TEXT,
+	ADD COLUMN IF NOT EXISTS categories TEXT[],
+	ADD COLUMN IF NOT EXISTS thread_id TEXT,
+	ADD COLUMN IF NOT EXISTS premium BOOLEAN NOT NULL DEFAULT false,
+	ADD COLUMN IF NOT EXISTS mirror_url TEXT,
+	ADD COLUMN IF NOT EXISTS raw JSONB;
+
+CREATE INDEX IF NOT EXISTS articles_topic_sent_at_idx ON articles (topic, sent_at);
+CREATE INDEX IF NOT EXISTS articles_source_idx ON articles (source);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS articles_dedupe_key_idx;
+
+ALTER TABLE articles DROP COLUMN IF EXISTS dedupe_key;
@@ -0,0 +1,10 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS dedupe_key TEXT;
+
+-- Same keys as source.URLKey and source.TitleKey: videos were deduplicated by URL, articles by title
+UPDATE articles SET dedupe_key = CASE
+	WHEN url LIKE '%youtube.com/watch?%' THEN 'url:' || url
+	ELSE 'title:' || title
+END
+WHERE dedupe_key IS NULL;
+
+CREATE INDEX IF NOT EXISTS articles_dedupe_key_idx ON articles (dedupe_key);
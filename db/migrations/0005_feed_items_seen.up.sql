@@ -0,0 +1,6 @@
+-- First time each item was seen, used as its date when the feed gives none
+CREATE TABLE IF NOT EXISTS feed_items_seen (
+	dedupe_key TEXT PRIMARY KEY,
+	feed_url TEXT NOT NULL,
+	first_seen_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,8 @@
+-- Validators of every feed, so unchanged feeds can be skipped with a conditional request
+CREATE TABLE IF NOT EXISTS feed_cache (
+	feed_url TEXT PRIMARY KEY,
+	etag TEXT NOT NULL DEFAULT '',
+	last_modified TEXT NOT NULL DEFAULT '',
+	content_hash TEXT NOT NULL DEFAULT '',
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
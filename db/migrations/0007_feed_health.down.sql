@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS feed_health;
+DROP TABLE IF EXISTS feed_checks;
@@ -0,0 +1,24 @@
+-- Outcome of every fetch of every feed
+CREATE TABLE IF NOT EXISTS feed_checks (
+	id SERIAL PRIMARY KEY,
+	feed_url TEXT NOT NULL,
+	checked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	ok BOOLEAN NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	latency_ms INTEGER NOT NULL DEFAULT 0,
+	items INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS feed_checks_feed_url_checked_at_idx ON feed_checks (feed_url, checked_at);
+
+-- Current health of every feed, derived from its checks
+CREATE TABLE IF NOT EXISTS feed_health (
+	feed_url TEXT PRIMARY KEY,
+	consecutive_failures INTEGER NOT NULL DEFAULT 0,
+	last_error TEXT NOT NULL DEFAULT '',
+	last_checked_at TIMESTAMPTZ,
+	last_success_at TIMESTAMPTZ,
+	last_latency_ms INTEGER NOT NULL DEFAULT 0,
+	last_item_at TIMESTAMPTZ,
+	quarantined_until TIMESTAMPTZ
+);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS articles_source_guid_idx;
+DROP INDEX IF EXISTS articles_normalized_url_idx;
+DROP INDEX IF EXISTS articles_dedupe_key_key;
+
+-- Restore the keys of migration 0004: videos by URL, articles by title
+UPDATE articles SET dedupe_key = CASE
+	WHEN url LIKE '%youtube.com/watch?%' THEN 'url:' || url
+	ELSE 'title:' || title
+END;
+
+CREATE INDEX IF NOT EXISTS articles_dedupe_key_idx ON articles (dedupe_key);
+CREATE UNIQUE INDEX IF NOT EXISTS articles_normalized_url_key ON articles (normalized_url);
+
+ALTER TABLE articles DROP COLUMN IF EXISTS guid;
@@ -0,0 +1,11 @@
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS guid TEXT;
+
+UPDATE articles SET guid = raw->>'guid' WHERE guid IS NULL AND raw IS NOT NULL;
+
+-- The normalized URLs and dedupe keys are recomputed with utils.CanonicalizeURL by the Go step of this
+-- migration, which then creates the unique index articles_dedupe_key_key.
+DROP INDEX IF EXISTS articles_dedupe_key_idx;
+
+DROP INDEX IF EXISTS articles_normalized_url_key;
+CREATE INDEX IF NOT EXISTS articles_normalized_url_idx ON articles (normalized_url);
+CREATE INDEX IF NOT EXISTS articles_source_guid_idx ON articles (source, guid);
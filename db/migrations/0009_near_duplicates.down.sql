@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS articles_duplicate_of_idx;
+
+ALTER TABLE articles
+	DROP COLUMN IF EXISTS duplicate_of,
+	DROP COLUMN IF EXISTS message,
+	DROP COLUMN IF EXISTS message_id,
+	DROP COLUMN IF EXISTS simhash;
@@ -0,0 +1,7 @@
+ALTER TABLE articles
+	ADD COLUMN IF NOT EXISTS simhash BIGINT,
+	ADD COLUMN IF NOT EXISTS message_id BIGINT,
+	ADD COLUMN IF NOT EXISTS message TEXT,
+	ADD COLUMN IF NOT EXISTS duplicate_of INTEGER REFERENCES articles (id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS articles_duplicate_of_idx ON articles (duplicate_of);
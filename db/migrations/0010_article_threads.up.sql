@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS article_threads (
+	article_id INTEGER NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+	topic TEXT NOT NULL,
+	thread_id TEXT,
+	message_id BIGINT,
+	sent_at TIMESTAMPTZ,
+	PRIMARY KEY (article_id, topic)
+);
+
+CREATE INDEX IF NOT EXISTS article_threads_topic_idx ON article_threads (topic);
+
+-- Articles stored so far were posted to their main thread only
+INSERT INTO article_threads (article_id, topic, thread_id, message_id, sent_at)
+SELECT id, topic, thread_id, message_id, sent_at FROM articles WHERE topic IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- The normalized URLs cleared on duplicates are not restored
+DROP INDEX IF EXISTS articles_normalized_url_key;
+CREATE INDEX IF NOT EXISTS articles_normalized_url_idx ON articles (normalized_url);
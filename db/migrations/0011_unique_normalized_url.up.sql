@@ -0,0 +1,15 @@
+-- Migration 0008 re-keyed the articles with canonical URLs, which several rows can share. The oldest row keeps
+-- the normalized URL and the later ones are marked as its duplicates, so no article is deleted.
+UPDATE articles a SET
+	duplicate_of = COALESCE(a.duplicate_of, oldest.id),
+	normalized_url = NULL
+FROM (
+	SELECT normalized_url, min(id) AS id FROM articles
+	WHERE normalized_url IS NOT NULL
+	GROUP BY normalized_url
+	HAVING count(*) > 1
+) AS oldest
+WHERE a.normalized_url = oldest.normalized_url AND a.id > oldest.id;
+
+DROP INDEX IF EXISTS articles_normalized_url_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS articles_normalized_url_key ON articles (normalized_url);
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Count is the number of articles for one key of a grouping.
+type Count struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// Stats summarizes the article archive.
+type Stats struct {
+	Total      int     `json:"total"`
+	PerDay     []Count `json:"per_day"`
+	PerWeek    []Count `json:"per_week"`
+	PerTopic   []Count `json:"per_topic"`
+	TopFeeds   []Count `json:"top_feeds"`
+	TopAuthors []Count `json:"top_authors"`
+	Premium    int     `json:"premium"`
+	Free       int     `json:"free"`
+	Videos     int     `json:"videos"`
+	Written    int     `json:"written"`
+}
+
+// articleTime is the time an article is counted at: when it was sent, or when it was stored for older rows.
+// The columns are qualified because article_threads has a sent_at column too.
+const articleTime = "COALESCE(articles.sent_at, articles.created_at)"
+
+// articleThreads joins every article with the threads it was posted to, so an article counts once per topic.
+// Articles without any recorded thread count as unknown.
+const articleThreads = "articles LEFT JOIN article_threads ON article_threads.article_id = articles.id"
+
+// CollectStats computes the archive statistics for the articles stored since the given time.
+// A zero since covers the whole archive, and limit caps the length of every list. Near-duplicates
+// recorded without being posted are not counted.
+func CollectStats(db *sql.DB, since time.Time, limit int) (*Stats, error) {
+	stats := &Stats{}
+	sinceArg := nullTime(since)
+
+	err := db.QueryRow(`
+		SELECT count(*),
+			count(*) FILTER (WHERE premium),
+			count(*) FILTER (WHERE source_type = 'youtube')
+		FROM articles
+		WHERE duplicate_of IS NULL AND ($1::timestamptz IS NULL OR `+articleTime+` >= $1)
+	`, sinceArg).Scan(&stats.Total, &stats.Premium, &stats.Videos)
+	if err != nil {
+		return nil, err
+	}
+	stats.Free = stats.Total - stats.Premium
+	stats.Written = stats.Total - stats.Videos
+
+	groupings := []struct {
+		target  *[]Count
+		from    string
+		key     string
+		orderBy string
+	}{
+		{&stats.PerDay, "articles", "to_char(date_trunc('day', " + articleTime + "), 'YYYY-MM-DD')", "key DESC"},
+		{&stats.PerWeek, "articles", "to_char(date_trunc('week', " + articleTime + "), 'IYYY-\"W\"IW')", "key DESC"},
+		{&stats.PerTopic, articleThreads, "COALESCE(NULLIF(article_threads.topic, ''), 'unknown')", "count DESC, key"},
+		{&stats.TopFeeds, "articles", "COALESCE(NULLIF(source, ''), 'unknown')", "count DESC, key"},
+		{&stats.TopAuthors, "articles", "NULLIF(author, '')", "count DESC, key"},
+	}
+	for _, grouping := range groupings {
+		counts, err := countBy(db, grouping.from, grouping.key, grouping.orderBy, sinceArg, limit)
+		if err != nil {
+			return nil, err
+		}
+		*grouping.target = counts
+	}
+
+	return stats, nil
+}
+
+// countBy counts the rows of from per value of the key expression, skipping NULL keys and near-duplicates.
+func countBy(db *sql.DB, from, key, orderBy string, since *time.Time, limit int) ([]Count, error) {
+	query := fmt.Sprintf(`
+		SELECT key, count FROM (
+			SELECT %s AS key, count(*) AS count
+			FROM %s
+			WHERE duplicate_of IS NULL AND ($1::timestamptz IS NULL OR %s >= $1)
+			GROUP BY 1
+		) AS grouped
+		WHERE key IS NOT NULL
+		ORDER BY %s
+		LIMIT $2
+	`, key, from, articleTime, orderBy)
+
+	rows, err := db.Query(query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []Count
+	for rows.Next() {
+		var count Count
+		if err := rows.Scan(&count.Key, &count.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCollectStats checks the totals and that every grouping is read with the time range and limit,
+// leaving out near-duplicates.
+func TestCollectStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT count\\(\\*\\),.+FROM articles WHERE duplicate_of IS NULL AND").WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"count", "premium", "videos"}).AddRow(10, 3, 4))
+
+	groupings := []struct {
+		key  string
+		rows *sqlmock.Rows
+	}{
+		{"date_trunc\\('day'", sqlmock.NewRows([]string{"key", "count"}).AddRow("2025-02-05", 6).AddRow("2025-02-04", 4)},
+		{"date_trunc\\('week'", sqlmock.NewRows([]string{"key", "count"}).AddRow("2025-W06", 10)},
+		{"NULLIF\\(article_threads.topic, ''\\).+ FROM articles LEFT JOIN article_threads ON",
+			sqlmock.NewRows([]string{"key", "count"}).AddRow("XSS_THREAD_ID", 7).AddRow("unknown", 3)},
+		{"NULLIF\\(source, ''\\)", sqlmock.NewRows([]string{"key", "count"}).AddRow("https://medium.com/feed/tag/xss", 5)},
+		{"NULLIF\\(author, ''\\)", sqlmock.NewRows([]string{"key", "count"})},
+	}
+	for _, grouping := range groupings {
+		mock.ExpectQuery("SELECT key, count FROM \\( SELECT .*"+grouping.key+".+ WHERE duplicate_of IS NULL AND").
+			WithArgs(since, 5).WillReturnRows(grouping.rows)
+	}
+
+	stats, err := CollectStats(db, since, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, stats.Total)
+	assert.Equal(t, 3, stats.Premium)
+	assert.Equal(t, 7, stats.Free)
+	assert.Equal(t, 4, stats.Videos)
+	assert.Equal(t, 6, stats.Written)
+	assert.Equal(t, []Count{{"2025-02-05", 6}, {"2025-02-04", 4}}, stats.PerDay)
+	assert.Equal(t, []Count{{"2025-W06", 10}}, stats.PerWeek)
+	assert.Equal(t, []Count{{"XSS_THREAD_ID", 7}, {"unknown", 3}}, stats.PerTopic)
+	assert.Equal(t, []Count{{"https://medium.com/feed/tag/xss", 5}}, stats.TopFeeds)
+	assert.Empty(t, stats.TopAuthors)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// TestCountByWholeArchive checks that a zero since is passed as NULL so the whole archive is counted.
+func TestCountByWholeArchive(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT key, count FROM .+ WHERE duplicate_of IS NULL AND").WithArgs(nil, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"key", "count"}).AddRow("Jane Doe", 2))
+
+	counts, err := countBy(db, "articles", "NULLIF(author, '')", "count DESC, key", nullTime(time.Time{}), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Count{{"Jane Doe", 2}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+	"writeup-finder.go/utils"
+)
+
+// migrationSteps are run after the up SQL of the migration with the same version, in the same transaction,
+// for data changes that must follow the rules of the Go code rather than an approximation in SQL.
+var migrationSteps = map[int]func(tx *sql.Tx) error{
+	8: rekeyArticles,
+}
+
+// rekeyArticles recomputes the normalized URL and dedupe key of every article with utils.CanonicalizeURL,
+// so stored articles have the same keys as new ones, then builds the unique index on dedupe_key.
+// No article is deleted: when several share a key the oldest keeps it, and the others are left without one and reported.
+func rekeyArticles(tx *sql.Tx) error {
+	rows, err := tx.Query("SELECT id, url FROM articles WHERE url IS NOT NULL ORDER BY id")
+	if err != nil {
+		return err
+	}
+
+	type stored struct {
+		id  int64
+		url string
+	}
+	var articles []stored
+	for rows.Next() {
+		var article stored
+		if err := rows.Scan(&article.id, &article.url); err != nil {
+			rows.Close()
+			return err
+		}
+		articles = append(articles, article)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(articles))
+	duplicates := 0
+	for _, article := range articles {
+		canonical := utils.CanonicalizeURL(article.url)
+		key := nullString("url:" + canonical) // Same key as source.URLKey
+		if seen[*key] {
+			logrus.Warnf("[!] Article %d has the same canonical URL as an older one: %s", article.id, canonical)
+			key = nil
+			duplicates++
+		}
+		seen["url:"+canonical] = true
+
+		if _, err := tx.Exec("UPDATE articles SET normalized_url = $2, dedupe_key = $3 WHERE id = $1",
+			article.id, canonical, key); err != nil {
+			return err
+		}
+	}
+	if duplicates > 0 {
+		logrus.Warnf("[!] %d duplicate articles were kept without a dedupe key", duplicates)
+	}
+
+	_, err = tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS articles_dedupe_key_key ON articles (dedupe_key)")
+	return err
+}
@@ -0,0 +1,251 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"writeup-finder.go/db"
+)
+
+// Formats lists the supported export formats.
+var Formats = []string{"json", "csv", "markdown", "opml"}
+
+// Writer streams articles to an output one at a time.
+// Close must be called once all articles are written to terminate the document.
+type Writer interface {
+	Write(article *db.Article) error
+	Close() error
+}
+
+// ValidateFormat returns an error if format is not one of Formats or "md", the short name of markdown.
+func ValidateFormat(format string) error {
+	if format == "md" {
+		return nil
+	}
+	for _, supported := range Formats {
+		if format == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported export format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// NewWriter returns a Writer for the given format writing to out.
+func NewWriter(format string, out io.Writer) (Writer, error) {
+	switch format {
+	case "json":
+		return &jsonWriter{out: out}, nil
+	case "csv":
+		return newCSVWriter(out)
+	case "markdown", "md":
+		return &markdownWriter{out: out}, nil
+	case "opml":
+		return &opmlWriter{out: out}, nil
+	default:
+		return nil, ValidateFormat(format)
+	}
+}
+
+// Record is the exported representation of an article.
+type Record struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	MirrorURL   string     `json:"mirror_url,omitempty"`
+	Author      string     `json:"author,omitempty"`
+	Categories  []string   `json:"categories,omitempty"`
+	Topic       string     `json:"topic,omitempty"`
+	ThreadID    string     `json:"thread_id,omitempty"`
+	Source      string     `json:"source,omitempty"`
+	SourceType  string     `json:"source_type,omitempty"`
+	Premium     bool       `json:"premium"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// NewRecord converts a stored article into its exported representation.
+func NewRecord(article *db.Article) Record {
+	record := Record{
+		ID:         article.ID,
+		Title:      article.Title,
+		URL:        article.URL,
+		MirrorURL:  article.MirrorURL,
+		Author:     article.Author,
+		Categories: article.Categories,
+		Topic:      article.Topic,
+		ThreadID:   article.ThreadID,
+		Source:     article.FeedURL,
+		SourceType: article.SourceType,
+		Premium:    article.Premium,
+		SentAt:     article.SentAt,
+		CreatedAt:  article.CreatedAt,
+	}
+	if !article.PublishedAt.IsZero() {
+		record.PublishedAt = &article.PublishedAt
+	}
+	return record
+}
+
+// published returns the publication date of an article, falling back to the time it was stored.
+func published(article *db.Article) time.Time {
+	if !article.PublishedAt.IsZero() {
+		return article.PublishedAt
+	}
+	return article.CreatedAt
+}
+
+// jsonWriter writes a JSON array, one element per line.
+type jsonWriter struct {
+	out   io.Writer
+	count int
+}
+
+func (w *jsonWriter) Write(article *db.Article) error {
+	data, err := json.Marshal(NewRecord(article))
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if w.count == 0 {
+		separator = "[\n"
+	}
+	w.count++
+
+	_, err = fmt.Fprintf(w.out, "%s  %s", separator, data)
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	if w.count == 0 {
+		_, err := io.WriteString(w.out, "[]\n")
+		return err
+	}
+	_, err := io.WriteString(w.out, "\n]\n")
+	return err
+}
+
+// csvWriter writes a header row followed by one row per article.
+type csvWriter struct {
+	out *csv.Writer
+}
+
+func newCSVWriter(out io.Writer) (*csvWriter, error) {
+	w := &csvWriter{out: csv.NewWriter(out)}
+	err := w.out.Write([]string{"id", "published_at", "title", "url", "mirror_url", "author", "categories", "topic", "thread_id", "source", "source_type", "premium"})
+	return w, err
+}
+
+func (w *csvWriter) Write(article *db.Article) error {
+	return w.out.Write([]string{
+		strconv.FormatInt(article.ID, 10),
+		published(article).Format(time.RFC3339),
+		article.Title,
+		article.URL,
+		article.MirrorURL,
+		article.Author,
+		strings.Join(article.Categories, ";"),
+		article.Topic,
+		article.ThreadID,
+		article.FeedURL,
+		article.SourceType,
+		strconv.FormatBool(article.Premium),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	w.out.Flush()
+	return w.out.Error()
+}
+
+// markdownWriter writes a reading list as a Markdown table.
+type markdownWriter struct {
+	out   io.Writer
+	count int
+}
+
+func (w *markdownWriter) Write(article *db.Article) error {
+	if w.count == 0 {
+		if _, err := io.WriteString(w.out, "| Published | Title | Author | Topic |\n| --- | --- | --- | --- |\n"); err != nil {
+			return err
+		}
+	}
+	w.count++
+
+	_, err := fmt.Fprintf(w.out, "| %s | [%s](%s) | %s | %s |\n",
+		published(article).Format("2006-01-02"), markdownEscape(article.Title), article.Link(),
+		markdownEscape(article.Author), article.Topic)
+	return err
+}
+
+func (w *markdownWriter) Close() error {
+	if w.count == 0 {
+		_, err := io.WriteString(w.out, "_No articles._\n")
+		return err
+	}
+	return nil
+}
+
+// markdownEscape escapes the characters that would break a table cell or a link label.
+func markdownEscape(text string) string {
+	return strings.NewReplacer("|", "\\|", "[", "\\[", "]", "\\]", "\n", " ").Replace(text)
+}
+
+// opmlWriter writes an OPML outline with one link entry per article.
+type opmlWriter struct {
+	out     io.Writer
+	started bool
+}
+
+func (w *opmlWriter) begin() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	_, err := fmt.Fprintf(w.out, "%s<opml version=\"2.0\">\n  <head>\n    <title>Writeup Finder export</title>\n    <dateCreated>%s</dateCreated>\n  </head>\n  <body>\n",
+		xml.Header, time.Now().Format(time.RFC1123Z))
+	return err
+}
+
+func (w *opmlWriter) Write(article *db.Article) error {
+	if err := w.begin(); err != nil {
+		return err
+	}
+
+	outline := struct {
+		XMLName  xml.Name `xml:"outline"`
+		Type     string   `xml:"type,attr"`
+		Text     string   `xml:"text,attr"`
+		URL      string   `xml:"url,attr"`
+		Created  string   `xml:"created,attr"`
+		Category string   `xml:"category,attr,omitempty"`
+	}{
+		Type:     "link",
+		Text:     article.Title,
+		URL:      article.Link(),
+		Created:  published(article).Format(time.RFC1123Z),
+		Category: article.Topic,
+	}
+
+	data, err := xml.Marshal(outline)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w.out, "    %s\n", data)
+	return err
+}
+
+func (w *opmlWriter) Close() error {
+	if err := w.begin(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.out, "  </body>\n</opml>\n")
+	return err
+}
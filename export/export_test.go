@@ -0,0 +1,60 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"writeup-finder.go/db"
+)
+
+var sample = []*db.Article{
+	{ID: 1, Title: "SSRF in a PDF generator", URL: "https://medium.com/p/1", Topic: "VULNERABILITIES_THREAD_ID", PublishedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+	{ID: 2, Title: "Pipes | in titles", URL: "https://medium.com/p/2", MirrorURL: "https://freedium.cfd/p/2", Premium: true, CreatedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+}
+
+// writeAll streams the sample articles through a writer of the given format.
+func writeAll(t *testing.T, format string, articles []*db.Article) string {
+	var out bytes.Buffer
+	writer, err := NewWriter(format, &out)
+	assert.NoError(t, err)
+	for _, article := range articles {
+		assert.NoError(t, writer.Write(article))
+	}
+	assert.NoError(t, writer.Close())
+	return out.String()
+}
+
+// TestJSONWriter checks that the streamed output is a valid JSON array.
+func TestJSONWriter(t *testing.T) {
+	var records []Record
+	assert.NoError(t, json.Unmarshal([]byte(writeAll(t, "json", sample)), &records))
+	assert.Len(t, records, 2)
+	assert.Equal(t, "https://freedium.cfd/p/2", records[1].MirrorURL)
+
+	assert.NoError(t, json.Unmarshal([]byte(writeAll(t, "json", nil)), &records))
+	assert.Empty(t, records)
+}
+
+// TestCSVAndMarkdownWriters checks the header and row layout of the tabular formats.
+func TestCSVAndMarkdownWriters(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(writeAll(t, "csv", sample)), "\n")
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "id,published_at,title,url"))
+	assert.True(t, strings.HasPrefix(lines[2], "2,2025-01-03T00:00:00Z,Pipes | in titles,"))
+
+	markdown := writeAll(t, "markdown", sample)
+	assert.Contains(t, markdown, "| 2025-01-03 | [Pipes \\| in titles](https://freedium.cfd/p/2) |")
+}
+
+// TestUnknownFormat checks that an unsupported format is rejected.
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("yaml", &bytes.Buffer{})
+	assert.Error(t, err)
+	assert.Error(t, ValidateFormat("yaml"))
+	assert.NoError(t, ValidateFormat("md"))
+	assert.NoError(t, ValidateFormat("opml"))
+}
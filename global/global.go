@@ -1,17 +1,44 @@
 package global
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 const (
-	DataFolder = "data/"
-	UrlFile    = DataFolder + "url.txt"
-	DateFormat = "2006-01-02"
+	DataFolder          = "data/"
+	DefaultUrlFile      = DataFolder + "url.txt"
+	DefaultKeywordsFile = DataFolder + "keywords.json"
+	DateFormat          = "2006-01-02"
 )
 
 var (
+	UrlFile            = DefaultUrlFile
+	KeywordsFile       = DefaultKeywordsFile
+	ConfigPath         string
 	DB                 *sql.DB
 	UseDatabase        bool
 	SendToTelegramFlag bool
 	ProxyURL           string
 	Help               bool
+	ServeInterval      time.Duration
+	FeedFixturesDir    string
+	DryRun             bool
+	Since              string
+	MaxAge             time.Duration
+	SendInterval       time.Duration
+	BackfillFrom       string
+	BackfillTo         string
+	MigrateSteps       int
+	ExportFormat       string
+	ExportTopic        string
+	ExportOutput       string
+	StatsJSON          bool
+	StatsLimit         int
+	Workers            int
+	HealthStaleAfter   time.Duration
+	HealthAll          bool
+	ExplainDescription string
+	ExplainContent     string
+	ExplainCategories  []string
 )
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fatih/color"
+	"writeup-finder.go/db"
+	"writeup-finder.go/global"
+	"writeup-finder.go/source"
+	"writeup-finder.go/telegram"
+	"writeup-finder.go/utils"
+)
+
+// fingerprint returns the fingerprint compared to find near duplicates of an article, or zero for articles
+// that go to a fixed thread. Videos, releases and CVEs of one product read alike without being copies.
+func fingerprint(article *source.Article) uint64 {
+	if article.Thread != "" {
+		return 0
+	}
+	return article.Fingerprint()
+}
+
+// nearDuplicate returns the article stored in the last NEAR_DUPLICATE_DAYS that the given one is a copy of,
+// such as the same writeup cross-posted on Medium and Hashnode under a retouched title, or nil if there is none.
+func nearDuplicate(article *source.Article, database *sql.DB) *db.Article {
+	hash := fingerprint(article)
+	days := db.NearDuplicateDays()
+	if database == nil || hash == 0 || days == 0 {
+		return nil
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	original, err := db.FindNearDuplicate(database, hash, since, db.NearDuplicateDistance())
+	utils.HandleError(err, "Error looking for near duplicates", false)
+	return original
+}
+
+// sentMessages returns the messages the original article was posted with, one per thread recorded in
+// article_threads, falling back to its main message when the threads cannot be loaded.
+func sentMessages(original *db.Article, database *sql.DB) []db.ArticleThread {
+	threads, err := db.SentThreads(database, original.ID)
+	utils.HandleError(err, "Error loading the threads of the original article", false)
+	if len(threads) == 0 && original.MessageID != 0 {
+		threads = []db.ArticleThread{{Topic: original.Topic, MessageID: original.MessageID}}
+	}
+	return threads
+}
+
+// HandleNearDuplicate records an article as a copy of original instead of posting it.
+// In the "also-on" mode every message the original was posted with gets an "Also on" link to the copy.
+// Edits are spaced out by the pacer of the run. In dry-run mode it only prints what it would do.
+func HandleNearDuplicate(article *source.Article, original *db.Article, database *sql.DB, pacer *Pacer) {
+	utils.PrintPretty(fmt.Sprintf("Near duplicate of %s: %s", original.URL, article.URL), color.FgYellow, false)
+
+	var messages []db.ArticleThread
+	if db.NearDuplicateMode() == db.NearDuplicateAlsoOn {
+		messages = sentMessages(original, database)
+	}
+	if global.DryRun {
+		for _, thread := range messages {
+			fmt.Println(color.CyanString("[dry-run] Would add an \"Also on\" link to message %d in %s", thread.MessageID, thread.Topic))
+		}
+		return
+	}
+
+	if len(messages) > 0 && global.SendToTelegramFlag {
+		message := original.Message + "\nAlso on: " + article.URL
+		edited := false
+		for _, thread := range messages {
+			pacer.Wait()
+			if err := telegram.EditTelegramMessage(message, global.ProxyURL, thread.MessageID, thread.Topic); err != nil {
+				log.Printf("Error adding %s to message %d in %s: %v", article.URL, thread.MessageID, thread.Topic, err)
+			} else {
+				edited = true
+			}
+		}
+		if edited && global.UseDatabase {
+			utils.HandleError(db.UpdateMessage(database, original.ID, message), "Error saving edited message", false)
+		}
+	}
+
+	if global.UseDatabase {
+		record := NewRecord(article)
+		record.DuplicateOf = &original.ID
+		db.SaveUrlToDB(database, record)
+	}
+}
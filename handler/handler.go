@@ -2,33 +2,209 @@ package handler
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/db"
+	"writeup-finder.go/global"
+	"writeup-finder.go/source"
+	_ "writeup-finder.go/source/all" // Register the built-in sources
 	"writeup-finder.go/utils"
 )
 
-// ProcessUrls iterates over a list of URLs and processes each one based on its type (Medium or YouTube).
-func ProcessUrls(urlList []string, today time.Time, database *sql.DB) int {
+// FeedBudgets are the request rates allowed per platform while fetching feeds, keyed by source.RateLimitKey.
+// Medium throttles aggressively, and Reddit only allows a few unauthenticated requests per minute.
+var FeedBudgets = map[string]utils.RateBudget{
+	"medium":  {Rate: 1, Burst: 3},
+	"youtube": {Rate: 2, Burst: 4},
+	"reddit":  {Rate: 0.2, Burst: 2},
+}
+
+// DefaultFeedBudget is the request rate allowed for every host without its own budget.
+var DefaultFeedBudget = utils.RateBudget{Rate: 1, Burst: 2}
+
+// fetchResult is the outcome of fetching one feed.
+type fetchResult struct {
+	source    source.Source
+	items     []*gofeed.Item
+	err       error
+	checkedAt time.Time
+	latency   time.Duration
+	cache     *utils.FeedCacheEntry // Validators to store once the feed is processed, nil if caching is off
+}
+
+// ProcessUrls fetches every feed with a bounded pool of workers and processes it with the source that matches its URL.
+// Fetches are throttled per host, while deduplication and delivery happen in a single stage that
+// handles the feeds one after another in list order, so topics are not flooded.
+// Only articles published within the window are considered.
+// Quarantined feeds are skipped, and the outcome of every fetch is recorded in the feed health tables.
+// The cache entry of a feed is only stored after its items are handled, so an interrupted run loses nothing.
+// Telegram messages of the run are spaced out by --send-interval.
+func ProcessUrls(urlList []string, window TimeWindow, database *sql.DB) int {
+	urlList = skipQuarantined(urlList, database)
+	results := FetchFeeds(urlList, global.Workers)
+	pacer := NewPacer(global.SendInterval)
 	articlesFound := 0
 
 	for i, url := range urlList {
-		utils.PrintPretty(fmt.Sprintf("Processing feed: %s", url), color.FgMagenta, false)
+		result := <-results[i]
+		recordCheck(database, url, result)
+		utils.PrintPretty(fmt.Sprintf("Processing %s feed: %s", result.source.Name(), url), color.FgMagenta, false)
 
-		// Determine the type of feed and process accordingly
-		if IsYouTubeFeed(url) {
-			videosFound := ProcessYouTubeFeed(url, today, database)
-			articlesFound += videosFound
-		} else {
-			articlesFound += ProcessMediumFeed(url, today, database)
+		switch {
+		case errors.Is(result.err, utils.ErrNotModified):
+			utils.PrintPretty(fmt.Sprintf("Feed unchanged since the last run: %s", url), color.FgYellow, false)
+		case result.err != nil:
+			log.Printf("Error fetching feed %s: %v", url, result.err)
+			continue
+		default:
+			articlesFound += ProcessItems(result.source, url, result.items, window, database, pacer)
 		}
 
-		// Delay processing of the next URL to prevent rate-limiting or server overload
-		if i < len(urlList)-1 {
-			time.Sleep(3 * time.Second)
+		if result.cache != nil {
+			utils.StoreCacheEntry(url, *result.cache)
 		}
 	}
 
 	return articlesFound
 }
+
+// FetchFeeds starts fetching every feed with the given number of workers and returns one channel per feed,
+// in the same order as urlList, that receives the feed's result once it is fetched.
+func FetchFeeds(urlList []string, workers int) []chan fetchResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]chan fetchResult, len(urlList))
+	for i := range results {
+		results[i] = make(chan fetchResult, 1)
+	}
+
+	limiter := utils.NewHostLimiter(source.RateLimitKey, FeedBudgets, DefaultFeedBudget)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				url := urlList[i]
+				limiter.Wait(url)
+
+				result := fetchResult{source: source.For(url), checkedAt: time.Now()}
+				result.items, result.err = result.source.Fetch(url)
+				result.latency = time.Since(result.checkedAt)
+				if entry, ok := utils.TakeCacheEntry(url); ok {
+					result.cache = &entry
+				}
+				results[i] <- result
+			}
+		}()
+	}
+
+	go func() {
+		for i := range urlList {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// ProcessItems normalizes the items of one feed and handles the ones that are new, pacing messages with pacer.
+// It returns the number of articles handled.
+func ProcessItems(src source.Source, feedURL string, items []*gofeed.Item, window TimeWindow, database *sql.DB, pacer *Pacer) int {
+	articlesFound := 0
+	for _, item := range items {
+		article, err := src.Normalize(feedURL, item)
+		if err != nil {
+			log.Printf("Error reading item %q from %s: %v", item.Title, feedURL, err)
+			continue
+		}
+		article.SetDedupeKey()
+
+		if article.Published.IsZero() {
+			if database == nil && !global.DryRun {
+				log.Printf("Skipping undated item %s: the first-seen date needs --database", article.URL)
+				continue
+			}
+			firstSeen(article, database)
+		}
+
+		if !IsNewArticle(article, database, window) {
+			continue
+		}
+
+		if original := nearDuplicate(article, database); original != nil {
+			HandleNearDuplicate(article, original, database, pacer)
+			continue
+		}
+
+		message := src.Format(article)
+		if err := HandleArticle(article, message, database, pacer); err != nil {
+			log.Printf("Error handling article %s: %v", article.URL, err)
+			continue
+		}
+		fmt.Println(color.GreenString(message))
+		articlesFound++
+	}
+	return articlesFound
+}
+
+// skipQuarantined removes the feeds that are quarantined from the list.
+// Nothing is skipped without a database.
+func skipQuarantined(urlList []string, database *sql.DB) []string {
+	if database == nil {
+		return urlList
+	}
+
+	quarantined, err := db.QuarantinedFeeds(database, time.Now())
+	if err != nil {
+		utils.HandleError(err, "Error loading quarantined feeds", false)
+		return urlList
+	}
+
+	active := make([]string, 0, len(urlList))
+	for _, url := range urlList {
+		if until, ok := quarantined[url]; ok {
+			utils.PrintPretty(fmt.Sprintf("Skipping quarantined feed until %s: %s", until.Format(time.RFC3339), url), color.FgYellow, false)
+			continue
+		}
+		active = append(active, url)
+	}
+	return active
+}
+
+// recordCheck stores the outcome of a fetch in the feed health tables. An unchanged feed counts as a success.
+// Nothing is recorded without a database or in dry-run mode.
+func recordCheck(database *sql.DB, url string, result fetchResult) {
+	if database == nil || global.DryRun {
+		return
+	}
+
+	check := db.FeedCheck{
+		FeedURL:   url,
+		CheckedAt: result.checkedAt,
+		Latency:   result.latency,
+		Items:     len(result.items),
+	}
+	if !errors.Is(result.err, utils.ErrNotModified) {
+		check.Err = result.err
+	}
+	for _, item := range result.items {
+		if published := source.PublishedAt(item); published.After(check.LastItemAt) {
+			check.LastItemAt = published
+		}
+	}
+
+	utils.HandleError(db.RecordFeedCheck(database, check, db.QuarantineAfter(), db.CheckRetentionDays()), "Error recording feed health", false)
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"writeup-finder.go/source"
+	_ "writeup-finder.go/source/rss"
+	"writeup-finder.go/utils"
+)
+
+// TestFetchFeedsOrder fetches feeds that answer in reverse order with several workers
+// and checks that their results are still received in list order.
+func TestFetchFeedsOrder(t *testing.T) {
+	const feeds = 4
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&maxInFlight)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxInFlight, seen, current) {
+				break
+			}
+		}
+
+		var n int
+		fmt.Sscanf(r.URL.Path, "/feed/%d", &n)
+		time.Sleep(time.Duration(feeds-n) * 20 * time.Millisecond) // The first feed answers last
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed %d</title>
+<item><title>Post %d</title><link>https://blog.example.com/%d</link></item></channel></rss>`, n, n, n)
+	}))
+	defer server.Close()
+
+	key := source.RateLimitKey(server.URL)
+	FeedBudgets[key] = utils.RateBudget{Rate: 1000, Burst: feeds}
+	defer delete(FeedBudgets, key)
+
+	var urls []string
+	for n := 0; n < feeds; n++ {
+		urls = append(urls, fmt.Sprintf("%s/feed/%d", server.URL, n))
+	}
+
+	results := FetchFeeds(urls, feeds)
+	for n, result := range results {
+		fetched := <-result
+		assert.NoError(t, fetched.err)
+		if assert.Len(t, fetched.items, 1) {
+			assert.Equal(t, fmt.Sprintf("Post %d", n), fetched.items[0].Title)
+		}
+	}
+	assert.Greater(t, atomic.LoadInt32(&maxInFlight), int32(1), "feeds are fetched in parallel")
+}
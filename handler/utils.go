@@ -1,130 +1,185 @@
 package handler
 
 import (
-	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
+	"sync"
 	"time"
 
-	"github.com/chromedp/chromedp"
-	"github.com/mmcdole/gofeed"
+	"github.com/fatih/color"
 	"writeup-finder.go/db"
 	"writeup-finder.go/global"
+	"writeup-finder.go/source"
 	"writeup-finder.go/telegram"
 	"writeup-finder.go/utils"
 )
 
-// isYouTubeFeed determines if a given URL corresponds to a YouTube RSS feed.
-func IsYouTubeFeed(url string) bool {
-	return strings.HasPrefix(url, "https://www.youtube.com/feeds/")
-}
-
-// IsNewArticle checks if an article is new by comparing its publication date and database presence.
-func IsNewArticle(item *gofeed.Item, db *sql.DB, today time.Time) bool {
-	pubDate, err := utils.ParseDate(item.Published)
-	if err != nil {
+// IsNewArticle checks if an article is new by comparing its publication date with the window and its database presence.
+func IsNewArticle(article *source.Article, database *sql.DB, window TimeWindow) bool {
+	if !window.Contains(article.Published) {
 		return false
 	}
 
-	yesterday := today.AddDate(0, 0, -1)
-	isToday := pubDate.Format(global.DateFormat) == today.Format(global.DateFormat)
-	isYesterday := pubDate.Format(global.DateFormat) == yesterday.Format(global.DateFormat)
-
-	if !isToday && !isYesterday {
-		return false
+	// Without a database (dry-run) every article in the date window counts as new
+	if database == nil {
+		return true
 	}
 
-	var exists bool
-	query := "SELECT EXISTS(SELECT 1 FROM articles WHERE title = $1)"
-	err = db.QueryRow(query, item.Title).Scan(&exists)
-	utils.HandleError(err, "Error checking if article title exists in database", false)
+	exists, err := db.ArticleExists(database, article.DedupeKey, article.GUID, article.FeedURL)
+	utils.HandleError(err, "Error checking if article exists in database", false)
 
 	return !exists
 }
 
-// FormatArticleMessage creates a formatted string for an article's details.
-func FormatArticleMessage(item *gofeed.Item) string {
-	// Check if the GUID starts with "https://medium.com"
-	if !strings.HasPrefix(item.GUID, "https://medium.com") {
-		return fmt.Sprintf("\u25BA %s\nPublished: %s\nLink: %s", item.Title, item.Published, item.GUID)
-	}
-
-	premium, err := isPremium(item.GUID)
-	if err != nil {
-		log.Printf("Error checking premium status for URL %s: %v. Skipping URL.", item.GUID, err)
-		return fmt.Sprintf("\u25BA %s\nPublished: %s\nLink: %s", item.Title, item.Published, item.GUID)
-	}
-
-	// If the article is premium, change the domain
-	if premium {
-		item.GUID = strings.Replace(item.GUID, "https://medium.com", "https://freedium.cfd", 1)
+// firstSeen dates an article whose feed gives no date with the time it was first seen.
+// In dry-run mode, where nothing is recorded, that is always now. ProcessItems skips undated articles
+// when there is no database, since they would be sent again on every run.
+func firstSeen(article *source.Article, database *sql.DB) {
+	seen := time.Now()
+	if database != nil && !global.DryRun {
+		stored, err := db.FirstSeen(database, article.DedupeKey, article.FeedURL, seen)
+		utils.HandleError(err, "Error recording when the article was first seen", false)
+		if err == nil {
+			seen = stored
+		}
 	}
 
-	return fmt.Sprintf("\u25BA %s\nPublished: %s\nLink: %s", item.Title, item.Published, item.GUID)
+	article.Published = seen
+	article.PublishedText = seen.Format(time.RFC1123Z) + " (first seen)"
 }
 
-func isPremium(url string) (bool, error) {
-	// Custom user agent and allocator options
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
-		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
-		chromedp.Flag("no-sandbox", true),
-	)
-
-	// Create a new context with the allocator
-	ctx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer cancel()
-
-	// Create a new browser context
-	ctx, cancel = chromedp.NewContext(ctx)
-	defer cancel()
-
-	// Set a timeout for the entire operation
-	ctx, cancel = context.WithTimeout(ctx, 60*time.Second) // Extended timeout
-	defer cancel()
-
-	var isPremium bool
-
-	// Run the browser tasks
-	err := chromedp.Run(ctx,
-		chromedp.Navigate(url),
-		chromedp.WaitReady("body"), // Wait for the body to load
-		chromedp.Evaluate(`document.querySelectorAll('[aria-label="Close"]').forEach(btn => btn.click());`, nil), // Close popups
-		chromedp.Evaluate(`{
-			const xpathCheck = document.evaluate(
-				'//*[contains(text(), "Member-only story")]',
-				document,
-				null,
-				XPathResult.ANY_TYPE,
-				null
-			);
-			const hasMemberText = xpathCheck.iterateNext() !== null;
-
-			const hasGoldenStar = document.querySelector('svg[fill="#FFC017"]') !== null;
-
-			hasMemberText || hasGoldenStar;
-		}`, &isPremium),
-	)
-
-	if err != nil {
-		return false, fmt.Errorf("error checking premium status for %s: %v", url, err)
+// NewRecord builds the database record for a normalized article.
+func NewRecord(article *source.Article) *db.Article {
+	raw, err := json.Marshal(article.Item)
+	utils.HandleError(err, "Error encoding feed item", false)
+
+	return &db.Article{
+		URL:         article.URL,
+		Title:       article.Title,
+		DedupeKey:   article.DedupeKey,
+		GUID:        article.GUID,
+		Simhash:     fingerprint(article),
+		FeedURL:     article.FeedURL,
+		SourceType:  article.SourceType,
+		Author:      article.Author,
+		Categories:  article.Categories,
+		Premium:     article.Premium,
+		MirrorURL:   article.MirrorURL,
+		PublishedAt: article.Published,
+		FetchedAt:   time.Now(),
+		Raw:         raw,
 	}
-
-	return isPremium, nil
 }
 
 // HandleArticle manages sending an article to Telegram and saving it to the database if enabled.
-func HandleArticle(item *gofeed.Item, message string, database *sql.DB, isYoutube bool) error {
+// Articles routed to several topics, and articles with extra threads such as bounty reports,
+// are sent to each of them after the main thread.
+// The main thread, send time and message are recorded with the article when it is saved, and every thread in article_threads.
+// Messages are spaced out by the pacer of the run. In dry-run mode it only prints the message and the threads it would be sent to.
+func HandleArticle(article *source.Article, message string, database *sql.DB, pacer *Pacer) error {
+	record := NewRecord(article)
+	routes := articleRoutes(article)
+	record.ThreadID, record.Topic = routes[0].ThreadID, routes[0].Topic
+	extraRoutes := routes[1:]
+
+	if global.DryRun {
+		fmt.Println(color.CyanString("[dry-run] Would send to %s (thread %s):", record.Topic, record.ThreadID))
+		for _, route := range extraRoutes {
+			fmt.Println(color.CyanString("[dry-run] Would also send to %s (thread %s)", route.Topic, route.ThreadID))
+		}
+		return nil
+	}
+
+	mainThread := db.ArticleThread{Topic: record.Topic, ThreadID: record.ThreadID}
 	if global.SendToTelegramFlag {
+		pacer.Wait()
 		fmt.Println("Start Send to Telegram...")
 
-		telegram.SendToTelegram(message, global.ProxyURL, item.Title, isYoutube)
+		if messageID, err := telegram.SendToTelegram(message, global.ProxyURL, record.ThreadID, record.Topic); err == nil {
+			sentAt := time.Now()
+			record.SentAt = &sentAt
+			record.MessageID, record.Message = messageID, message
+			mainThread.MessageID, mainThread.SentAt = messageID, &sentAt
+		}
+	}
+	record.Threads = append(record.Threads, mainThread)
+
+	for _, route := range extraRoutes {
+		thread := db.ArticleThread{Topic: route.Topic, ThreadID: route.ThreadID}
+		if global.SendToTelegramFlag {
+			pacer.Wait()
+			if messageID, err := telegram.SendToTelegram(message, global.ProxyURL, route.ThreadID, route.Topic); err != nil {
+				log.Printf("Error sending %s to %s: %v", article.URL, route.Topic, err)
+			} else {
+				sentAt := time.Now()
+				thread.MessageID, thread.SentAt = messageID, &sentAt
+			}
+		}
+		record.Threads = append(record.Threads, thread)
 	}
 
 	if global.UseDatabase {
-		db.SaveUrlToDB(database, item.GUID, item.Title)
+		db.SaveUrlToDB(database, record)
 	}
 
 	return nil
 }
+
+// articleRoutes returns the threads an article is sent to, the main one first. MAX_TOPICS_PER_ARTICLE only
+// limits the topics picked by keywords; the extra threads of the source are always added.
+func articleRoutes(article *source.Article) []telegram.Route {
+	routes := telegram.ResolveThreads(article.KeywordText(), article.Thread, telegram.MaxTopics())
+	return append(routes[:1:1], extraRoutes(article, routes, telegram.ExclusiveTopics())...)
+}
+
+// extraRoutes returns the threads an article is sent to after the main one: the other topics it was routed to,
+// followed by its extra threads. Nothing is added to an exclusive main topic, extra threads of exclusive topics
+// are skipped, and every topic appears once, so nothing is sent twice.
+func extraRoutes(article *source.Article, routes []telegram.Route, exclusive map[string]bool) []telegram.Route {
+	if routes[0].Exclusive {
+		return nil
+	}
+
+	seen := map[string]bool{routes[0].Topic: true}
+	var extra []telegram.Route
+	for _, route := range routes[1:] {
+		if !seen[route.Topic] {
+			seen[route.Topic] = true
+			extra = append(extra, route)
+		}
+	}
+	for _, env := range article.ExtraThreads {
+		if !seen[env] && !exclusive[env] {
+			seen[env] = true
+			extra = append(extra, telegram.Route{ThreadID: utils.GetEnv(env), Topic: env})
+		}
+	}
+	return extra
+}
+
+// Pacer spaces out the Telegram messages of one run so topics are not flooded. It is safe for concurrent use.
+type Pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	last     time.Time
+}
+
+// NewPacer returns a pacer allowing one message per interval; zero turns pacing off.
+func NewPacer(interval time.Duration) *Pacer {
+	return &Pacer{interval: interval}
+}
+
+// Wait sleeps until the interval has passed since the previous message, then takes the slot.
+func (p *Pacer) Wait() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.interval > 0 && !p.last.IsZero() {
+		if wait := p.interval - time.Since(p.last); wait > 0 {
+			time.Sleep(wait)
+		}
+	}
+	p.last = time.Now()
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"writeup-finder.go/global"
+	"writeup-finder.go/source"
+	"writeup-finder.go/source/hackerone"
+	"writeup-finder.go/telegram"
+)
+
+// TestExtraRoutes checks that extra threads are added whatever the number of keyword topics,
+// once each, and never to or for an exclusive topic.
+func TestExtraRoutes(t *testing.T) {
+	article := &source.Article{ExtraThreads: []string{"MONEY_THREAD_ID", "CVE_THREAD_ID"}}
+
+	// A single keyword topic, as ResolveThreads returns with MAX_TOPICS_PER_ARTICLE at its default of 1
+	extra := extraRoutes(article, []telegram.Route{{Topic: "XSS_THREAD_ID"}}, nil)
+	assert.Equal(t, []telegram.Route{{Topic: "MONEY_THREAD_ID"}, {Topic: "CVE_THREAD_ID"}}, extra)
+
+	routes := []telegram.Route{{Topic: "XSS_THREAD_ID"}, {Topic: "MONEY_THREAD_ID"}}
+	assert.Len(t, extraRoutes(article, routes, nil), 2, "the money thread is only sent once")
+
+	exclusiveTopics := map[string]bool{"CVE_THREAD_ID": true}
+	assert.Equal(t, []telegram.Route{{Topic: "MONEY_THREAD_ID"}}, extraRoutes(article, routes[:1], exclusiveTopics))
+
+	exclusive := []telegram.Route{{Topic: "CLOUD_THREAD_ID", Exclusive: true}}
+	assert.Empty(t, extraRoutes(article, exclusive, nil))
+}
+
+// TestBountyRoutes fetches HackerOne reports and checks that, with default settings, a report that paid a bounty
+// is sent to its keyword topic and to the money thread, while an unpaid one only goes to its keyword topic.
+func TestBountyRoutes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../source/hackerone/testdata/hacktivity.json")
+	}))
+	defer server.Close()
+	t.Setenv("HACKERONE_API_URL", server.URL)
+	t.Setenv("MAX_TOPICS_PER_ARTICLE", "")
+	t.Setenv("MONEY_THREAD_ID", "4329")
+	global.KeywordsFile = "../data/keywords.json"
+	defer func() { global.KeywordsFile = global.DefaultKeywordsFile }()
+
+	feedURL := "https://hackerone.com/hacktivity"
+	src := hackerone.Source{}
+	items, err := src.Fetch(feedURL)
+	assert.NoError(t, err)
+	if !assert.Len(t, items, 2) {
+		return
+	}
+
+	paid, err := src.Normalize(feedURL, items[0])
+	assert.NoError(t, err)
+	routes := articleRoutes(paid)
+	if assert.Len(t, routes, 2) {
+		assert.NotEqual(t, hackerone.MoneyThread, routes[0].Topic)
+		assert.Equal(t, telegram.Route{ThreadID: "4329", Topic: hackerone.MoneyThread}, routes[1])
+	}
+
+	unpaid, err := src.Normalize(feedURL, items[1])
+	assert.NoError(t, err)
+	assert.Len(t, articleRoutes(unpaid), 1)
+}
+
+// TestPacer checks that messages are spaced out by the interval and that a zero interval does not wait.
+func TestPacer(t *testing.T) {
+	pacer := NewPacer(30 * time.Millisecond)
+	start := time.Now()
+	pacer.Wait()
+	pacer.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+	unpaced := NewPacer(0)
+	start = time.Now()
+	unpaced.Wait()
+	unpaced.Wait()
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+}
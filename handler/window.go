@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"time"
+)
+
+// TimeWindow is the range of publication times an article must fall in to be processed.
+type TimeWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// Contains reports whether t lies within the window, bounds included.
+func (w TimeWindow) Contains(t time.Time) bool {
+	return !t.Before(w.From) && !t.After(w.To)
+}
+
+// DefaultWindow returns the historical "published today or yesterday" window relative to now.
+func DefaultWindow(now time.Time) TimeWindow {
+	year, month, day := now.Date()
+	return TimeWindow{
+		From: time.Date(year, month, day-1, 0, 0, 0, 0, now.Location()),
+		To:   time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()).Add(-time.Nanosecond),
+	}
+}
+
+// MaxAgeWindow returns a window covering the last maxAge up to now.
+func MaxAgeWindow(now time.Time, maxAge time.Duration) TimeWindow {
+	return TimeWindow{From: now.Add(-maxAge), To: now}
+}
@@ -0,0 +1,14 @@
+// Package all registers every built-in source.
+// Import it for its side effects wherever feeds are dispatched to sources.
+package all
+
+import (
+	_ "writeup-finder.go/source/blog"
+	_ "writeup-finder.go/source/github"
+	_ "writeup-finder.go/source/hackerone"
+	_ "writeup-finder.go/source/medium"
+	_ "writeup-finder.go/source/nvd"
+	_ "writeup-finder.go/source/reddit"
+	_ "writeup-finder.go/source/rss"
+	_ "writeup-finder.go/source/youtube"
+)
@@ -0,0 +1,57 @@
+package all
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"writeup-finder.go/source"
+)
+
+// TestFor checks the source that handles each kind of entry found in url.txt.
+func TestFor(t *testing.T) {
+	cases := map[string]string{
+		"https://medium.com/feed/tag/bug-bounty":                                       "medium",
+		"https://medium.com/feed/@NahamSec":                                            "medium",
+		"https://vickieli.medium.com/feed":                                             "medium",
+		"https://infosecwriteups.com/feed":                                             "medium",
+		"https://www.youtube.com/feeds/videos.xml?channel_id=UCCZDt7MuC3Hzs6IH4xODLBw": "youtube",
+		"https://hashnode.com/n/bug-hunting/rss":                                       "hashnode",
+		"https://github.com/ffuf/ffuf/releases.atom":                                   "github",
+		"https://www.reddit.com/r/netsec/new.json":                                     "reddit",
+		"https://hackerone.com/hacktivity":                                             "hackerone",
+		"https://services.nvd.nist.gov/rest/json/cves/2.0":                             "nvd",
+		"https://dev.to/feed/tag/security":                                             "devto",
+		"https://blog.example.substack.com/feed":                                       "substack",
+		"https://example.ghost.io/rss/":                                                "ghost",
+		"https://example.com/rss/":                                                     "rss",
+	}
+
+	for feedURL, expected := range cases {
+		assert.Equal(t, expected, source.For(feedURL).Name(), feedURL)
+	}
+}
+
+// TestKind checks that feeds are grouped by the kind their source reports, or by source,
+// and that every kind is listed.
+func TestKind(t *testing.T) {
+	assert.Equal(t, "medium-tag", source.Kind("https://medium.com/feed/tag/xss"))
+	assert.Equal(t, "medium-user", source.Kind("https://medium.com/feed/@NahamSec"))
+	assert.Equal(t, "medium-publication", source.Kind("https://medium.com/feed/intigriti"))
+	assert.Equal(t, "youtube", source.Kind("https://www.youtube.com/feeds/videos.xml?channel_id=x"))
+	assert.Equal(t, "rss", source.Kind("https://example.com/rss/"))
+
+	kinds := source.Kinds()
+	assert.Subset(t, kinds, []string{"medium-tag", "medium-user", "medium-publication", "youtube", "hashnode", "rss"})
+	assert.NotContains(t, kinds, "medium")
+	assert.Equal(t, "rss", kinds[len(kinds)-1])
+}
+
+// TestRateLimitKey checks that the feeds of a platform share one budget and other feeds are limited per host.
+func TestRateLimitKey(t *testing.T) {
+	assert.Equal(t, "medium", source.RateLimitKey("https://vickieli.medium.com/feed"))
+	assert.Equal(t, "medium", source.RateLimitKey("https://infosecwriteups.com/feed"))
+	assert.Equal(t, "youtube", source.RateLimitKey("https://www.youtube.com/feeds/videos.xml?channel_id=x"))
+	assert.Equal(t, "reddit", source.RateLimitKey("https://old.reddit.com/r/netsec/new.json"))
+	assert.Equal(t, "hashnode", source.RateLimitKey("https://hashnode.com/n/recon/rss"))
+	assert.Equal(t, "blog.example.com", source.RateLimitKey("https://blog.example.com/feed"))
+}
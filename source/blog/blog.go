@@ -0,0 +1,73 @@
+// Package blog handles the blogging platforms whose feeds only differ by the hosts that serve them.
+package blog
+
+import (
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Platform is a blogging platform and the host rules of its feeds.
+type Platform struct {
+	Name    string   // Source type recorded for its posts
+	Hosts   []string // Hosts matched exactly
+	Domains []string // Domains whose subdomains are matched, e.g. the blogs of a hosting platform
+}
+
+// Platforms lists the blogging platforms. Their posts keep the item link, which is the canonical URL of the post
+// even on custom domains, since the GUIDs of Ghost and Hashnode are opaque IDs, and the dc:creator author and tags.
+var Platforms = []Platform{
+	{Name: "hashnode", Hosts: []string{"hashnode.com"}, Domains: []string{"hashnode.dev"}},
+	{Name: "devto", Hosts: []string{"dev.to"}},
+	{Name: "substack", Hosts: []string{"substack.com"}, Domains: []string{"substack.com"}},
+	{Name: "ghost", Domains: []string{"ghost.io"}},
+}
+
+// Source handles the feeds of one blogging platform.
+type Source struct {
+	platform Platform
+}
+
+func init() {
+	for _, platform := range Platforms {
+		source.Register(Source{platform: platform})
+	}
+}
+
+// Name returns the source type.
+func (s Source) Name() string {
+	return s.platform.Name
+}
+
+// Match reports whether the feed is served by one of the hosts of the platform.
+func (s Source) Match(feedURL string) bool {
+	host := source.Host(feedURL)
+	for _, h := range s.platform.Hosts {
+		if host == h {
+			return true
+		}
+	}
+	for _, domain := range s.platform.Domains {
+		if strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Fetch downloads the RSS feed.
+func (Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	return utils.FetchArticles(feedURL)
+}
+
+// Normalize keeps the post's link, its date, its tags and its author.
+func (s Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	return source.NewPost(s.platform.Name, feedURL, item)
+}
+
+// Format builds the default message.
+func (Source) Format(article *source.Article) string {
+	return source.FormatMessage(article)
+}
@@ -0,0 +1,66 @@
+package blog
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"writeup-finder.go/source"
+)
+
+// TestPlatforms reads a saved feed of every platform and checks the source it is dispatched to
+// and the date, author, tags and canonical URL of its post.
+func TestPlatforms(t *testing.T) {
+	cases := []struct {
+		feedURL    string
+		fixture    string
+		name       string
+		url        string
+		author     string
+		categories []string
+		published  time.Time
+	}{
+		{"https://dev.to/feed/tag/security", "devto.xml", "devto",
+			"https://dev.to/janedoe/finding-idors-with-autorize-3k2j", "Jane Doe",
+			[]string{"security", "bugbounty"}, time.Date(2025, 2, 3, 14, 5, 12, 0, time.UTC)},
+		{"https://recon.hashnode.dev/rss.xml", "hashnode.xml", "hashnode",
+			"https://blog.janedoe.dev/subdomain-takeover-forgotten-cname", "Jane Doe",
+			[]string{"recon", "takeover"}, time.Date(2025, 2, 4, 7, 0, 0, 0, time.UTC)},
+		{"https://janedoe.substack.com/feed", "substack.xml", "substack",
+			"https://newsletter.janedoe.com/p/open-redirect-to-ato", "Jane Doe",
+			nil, time.Date(2025, 2, 5, 16, 45, 0, 0, time.UTC)},
+		{"https://example.ghost.io/rss/", "ghost.xml", "ghost",
+			"https://example.ghost.io/ssrf-pdf-renderer/", "Jane Doe",
+			[]string{"Bug Bounty", "SSRF"}, time.Date(2025, 2, 6, 9, 30, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		src := source.For(c.feedURL)
+		if !assert.NotNil(t, src, c.feedURL) {
+			continue
+		}
+		assert.Equal(t, c.name, src.Name(), c.feedURL)
+
+		file, err := os.Open("testdata/" + c.fixture)
+		assert.NoError(t, err)
+		feed, err := gofeed.NewParser().Parse(file)
+		file.Close()
+		assert.NoError(t, err)
+
+		article, err := src.Normalize(c.feedURL, feed.Items[0])
+		assert.NoError(t, err)
+		assert.Equal(t, c.name, article.SourceType)
+		assert.Equal(t, c.url, article.URL)
+		assert.Equal(t, c.author, article.Author)
+		assert.Equal(t, c.categories, article.Categories)
+		assert.True(t, c.published.Equal(article.Published), c.feedURL)
+	}
+}
+
+// TestMatch checks that feeds of other hosts are left to the other sources.
+func TestMatch(t *testing.T) {
+	assert.Nil(t, source.For("https://blog.example.com/rss/"))
+	assert.Nil(t, source.For("https://substack.com.example.org/feed"))
+}
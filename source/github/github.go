@@ -0,0 +1,99 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for GitHub releases and tags.
+const Name = "github"
+
+// Thread is the thread variable every release is posted to, regardless of keywords.
+const Thread = "TOOLS_THREAD_ID"
+
+// ExcerptLength is the maximum number of characters of release notes included in a message.
+const ExcerptLength = 300
+
+// Source handles the releases.atom and tags.atom feeds of GitHub repositories.
+type Source struct{}
+
+func init() {
+	source.Register(Source{})
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match reports whether the feed is an Atom feed of a GitHub repository.
+func (Source) Match(feedURL string) bool {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return false
+	}
+	return strings.ToLower(parsed.Hostname()) == "github.com" && strings.HasSuffix(parsed.Path, ".atom")
+}
+
+// Fetch downloads the Atom feed.
+func (Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	return utils.FetchArticles(feedURL)
+}
+
+// Normalize titles the entry with the repository name, since release titles are often just the version.
+// Releases only carry an update date, are identified by their release page and always go to the tools thread.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	if item.Link == "" {
+		return nil, fmt.Errorf("no link in entry %q", item.Title)
+	}
+
+	article := source.NewArticle(Name, feedURL, item)
+	if repo := Repository(feedURL); repo != "" {
+		article.Title = repo + " " + item.Title
+	}
+	article.URL = item.Link
+	article.Published = source.PublishedAt(item)
+	article.Thread = Thread
+	return article, nil
+}
+
+// Format builds a message with the repository, the version and the start of the release notes.
+func (Source) Format(article *source.Article) string {
+	message := fmt.Sprintf("\u25BA %s\nVersion: %s\nPublished: %s\n", article.Title, Version(article.URL), article.PublishedText)
+	if excerpt := source.Excerpt(article.Content, ExcerptLength); excerpt != "" {
+		message += "\n" + excerpt + "\n\n"
+	}
+	return message + "Link: " + article.Link()
+}
+
+// Repository returns the owner/name of the repository a feed URL belongs to.
+func Repository(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[0] + "/" + parts[1]
+}
+
+// Version returns the tag name from a release or tag link such as https://github.com/owner/repo/releases/tag/v1.2.3.
+func Version(link string) string {
+	for _, marker := range []string{"/releases/tag/", "/tree/", "/commit/"} {
+		if i := strings.LastIndex(link, marker); i >= 0 {
+			version, err := url.PathUnescape(link[i+len(marker):])
+			if err != nil {
+				return link[i+len(marker):]
+			}
+			return version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package github
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+// releasesFeed is a trimmed releases.atom feed with one release.
+const releasesFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>tag:github.com,2008:https://github.com/projectdiscovery/nuclei/releases</id>
+  <title>Release notes from nuclei</title>
+  <updated>2025-02-04T10:00:00Z</updated>
+  <entry>
+    <id>tag:github.com,2008:Repository/250365014/v3.3.9</id>
+    <updated>2025-02-04T10:00:00Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/projectdiscovery/nuclei/releases/tag/v3.3.9"/>
+    <title>v3.3.9</title>
+    <content type="html">&lt;h2&gt;What&amp;#39;s Changed&lt;/h2&gt;
+&lt;ul&gt;&lt;li&gt;Added &lt;code&gt;-dast&lt;/code&gt; template filter&lt;/li&gt;&lt;/ul&gt;</content>
+    <author><name>ehsandeep</name></author>
+  </entry>
+</feed>`
+
+// TestNormalizeRelease checks that a release is titled with its repository, routed to the tools
+// thread and formatted with its version and release notes.
+func TestNormalizeRelease(t *testing.T) {
+	feedURL := "https://github.com/projectdiscovery/nuclei/releases.atom"
+	feed, err := gofeed.NewParser().Parse(strings.NewReader(releasesFeed))
+	assert.NoError(t, err)
+
+	src := Source{}
+	assert.True(t, src.Match(feedURL))
+	assert.False(t, src.Match("https://github.com/projectdiscovery/nuclei"))
+
+	article, err := src.Normalize(feedURL, feed.Items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "projectdiscovery/nuclei v3.3.9", article.Title)
+	assert.Equal(t, "https://github.com/projectdiscovery/nuclei/releases/tag/v3.3.9", article.URL)
+	assert.Equal(t, Thread, article.Thread)
+	assert.False(t, article.Published.IsZero())
+
+	message := src.Format(article)
+	assert.Contains(t, message, "Version: v3.3.9\n")
+	assert.Contains(t, message, "What's Changed Added -dast template filter")
+}
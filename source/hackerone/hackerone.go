@@ -0,0 +1,217 @@
+package hackerone
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/config"
+	"writeup-finder.go/global"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for disclosed HackerOne reports.
+const Name = "hackerone"
+
+// DefaultAPIURL is the Hacktivity endpoint of the Hacker API, used when HACKERONE_API_URL is not set.
+const DefaultAPIURL = "https://api.hackerone.com/v1/hackers/hacktivity"
+
+// MoneyThread is the thread variable reports with a bounty are also posted to.
+const MoneyThread = "MONEY_THREAD_ID"
+
+// Source handles the https://hackerone.com/hacktivity entry of the feed list by reading disclosed
+// reports from the Hacktivity API, which has no RSS equivalent.
+type Source struct{}
+
+func init() {
+	source.Register(Source{})
+}
+
+// hacktivity is the part of a Hacktivity API page the source reads.
+type hacktivity struct {
+	Data []struct {
+		Attributes struct {
+			Title       string   `json:"title"`
+			URL         string   `json:"url"`
+			DisclosedAt string   `json:"disclosed_at"`
+			CWE         string   `json:"cwe"`
+			CVEIDs      []string `json:"cve_ids"`
+			Severity    string   `json:"severity_rating"`
+			Bounty      *float64 `json:"total_awarded_amount"`
+		} `json:"attributes"`
+		Relationships struct {
+			Reporter struct {
+				Data struct {
+					Attributes struct {
+						Username string `json:"username"`
+					} `json:"attributes"`
+				} `json:"data"`
+			} `json:"reporter"`
+			Program struct {
+				Data struct {
+					Attributes struct {
+						Handle string `json:"handle"`
+						Name   string `json:"name"`
+					} `json:"attributes"`
+				} `json:"data"`
+			} `json:"program"`
+			Summary struct {
+				Data struct {
+					Attributes struct {
+						Summary string `json:"hacktivity_summary"`
+					} `json:"attributes"`
+				} `json:"data"`
+			} `json:"report_generated_content"`
+		} `json:"relationships"`
+	} `json:"data"`
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match reports whether the feed entry is the HackerOne Hacktivity page.
+func (Source) Match(feedURL string) bool {
+	host := source.Host(feedURL)
+	return host == "hackerone.com" || host == "www.hackerone.com"
+}
+
+// Fetch requests the latest disclosed reports from APIURL. The Hacker API needs credentials,
+// which are read from HACKERONE_API_USERNAME and HACKERONE_API_TOKEN when they are set.
+func (s Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	query := url.Values{}
+	query.Set("queryString", "disclosed:true")
+	query.Set("page[size]", "25")
+
+	req, err := http.NewRequest("GET", APIURL()+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if username := config.Lookup("HACKERONE_API_USERNAME"); username != "" {
+		req.SetBasicAuth(username, config.Lookup("HACKERONE_API_TOKEN"))
+	}
+
+	resp, err := utils.FeedClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP error: %s, status code: %d", resp.Status, resp.StatusCode)
+	}
+	return s.Parse(resp.Body)
+}
+
+// Parse reads a Hacktivity API page and maps every report to a feed item.
+// The program, severity, bounty and weakness are kept in the item's Custom map.
+func (Source) Parse(r io.Reader) ([]*gofeed.Item, error) {
+	var page hacktivity
+	if err := json.NewDecoder(r).Decode(&page); err != nil {
+		return nil, err
+	}
+
+	items := make([]*gofeed.Item, 0, len(page.Data))
+	for _, report := range page.Data {
+		attributes := report.Attributes
+		program := report.Relationships.Program.Data.Attributes
+
+		item := &gofeed.Item{
+			Title:       attributes.Title,
+			Link:        attributes.URL,
+			GUID:        attributes.URL,
+			Published:   attributes.DisclosedAt,
+			Description: report.Relationships.Summary.Data.Attributes.Summary,
+			Author:      &gofeed.Person{Name: report.Relationships.Reporter.Data.Attributes.Username},
+			Categories:  attributes.CVEIDs,
+			Custom: map[string]string{
+				"program":  program.Name,
+				"handle":   program.Handle,
+				"severity": attributes.Severity,
+				"cwe":      attributes.CWE,
+			},
+		}
+		if disclosed, err := time.Parse(time.RFC3339, attributes.DisclosedAt); err == nil {
+			item.PublishedParsed = &disclosed
+		}
+		if attributes.CWE != "" {
+			item.Categories = append([]string{attributes.CWE}, item.Categories...)
+		}
+		if attributes.Bounty != nil && *attributes.Bounty > 0 {
+			item.Custom["bounty"] = strconv.FormatFloat(*attributes.Bounty, 'f', -1, 64)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Normalize identifies reports by their URL. They are routed by keywords like any article,
+// and reports that paid a bounty are also posted to the money thread.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	if item.Link == "" {
+		return nil, fmt.Errorf("no link in report %q", item.Title)
+	}
+
+	article := source.NewArticle(Name, feedURL, item)
+	article.URL = item.Link
+	article.Published = source.PublishedAt(item)
+	if item.Custom["bounty"] != "" {
+		article.ExtraThreads = []string{MoneyThread}
+	}
+	return article, nil
+}
+
+// Format builds a message with the program, severity, bounty and weakness of the report.
+func (Source) Format(article *source.Article) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\u25BA %s\n", article.Title)
+
+	custom := map[string]string{}
+	if article.Item != nil {
+		custom = article.Item.Custom
+	}
+	for _, field := range []struct{ label, value string }{
+		{"Program", custom["program"]},
+		{"Severity", capitalize(custom["severity"])},
+		{"Bounty", formatBounty(custom["bounty"])},
+		{"Weakness", custom["cwe"]},
+	} {
+		if field.value != "" {
+			fmt.Fprintf(&b, "%s: %s\n", field.label, field.value)
+		}
+	}
+
+	fmt.Fprintf(&b, "Disclosed: %s\nLink: %s", article.Published.Format(global.DateFormat), article.Link())
+	return b.String()
+}
+
+// APIURL returns the Hacktivity endpoint.
+// It can be overridden with HACKERONE_API_URL, e.g. to point at a local stand-in.
+func APIURL() string {
+	return strings.TrimSuffix(config.LookupOr("HACKERONE_API_URL", DefaultAPIURL), "/")
+}
+
+// formatBounty renders a bounty amount in dollars, or an empty string when there is none.
+func formatBounty(amount string) string {
+	if amount == "" {
+		return ""
+	}
+	return "$" + amount
+}
+
+// capitalize upper-cases the first letter of a severity rating such as "high".
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
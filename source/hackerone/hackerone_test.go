@@ -0,0 +1,50 @@
+package hackerone
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFetchHacktivity serves a recorded Hacktivity page from a local stand-in of the API
+// and checks the captured report details and the bounty routing.
+func TestFetchHacktivity(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("queryString")
+		http.ServeFile(w, r, "testdata/hacktivity.json")
+	}))
+	defer server.Close()
+	t.Setenv("HACKERONE_API_URL", server.URL+"/v1/hackers/hacktivity")
+
+	feedURL := "https://hackerone.com/hacktivity"
+	src := Source{}
+	assert.True(t, src.Match(feedURL))
+
+	items, err := src.Fetch(feedURL)
+	assert.NoError(t, err)
+	assert.Equal(t, "disclosed:true", query)
+	assert.Len(t, items, 2)
+
+	paid, err := src.Normalize(feedURL, items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://hackerone.com/reports/2967450", paid.URL)
+	assert.Equal(t, "janedoe", paid.Author)
+	assert.Equal(t, []string{"Cross-site Scripting (XSS) - Stored", "CVE-2025-0475"}, paid.Categories)
+	assert.Equal(t, []string{MoneyThread}, paid.ExtraThreads)
+	assert.Empty(t, paid.Thread, "reports are routed by keywords")
+
+	message := src.Format(paid)
+	assert.Contains(t, message, "Program: GitLab\n")
+	assert.Contains(t, message, "Severity: High\n")
+	assert.Contains(t, message, "Bounty: $3500\n")
+	assert.Contains(t, message, "Weakness: Cross-site Scripting (XSS) - Stored\n")
+	assert.Contains(t, message, "Disclosed: 2025-02-04\n")
+
+	unpaid, err := src.Normalize(feedURL, items[1])
+	assert.NoError(t, err)
+	assert.Empty(t, unpaid.ExtraThreads)
+	assert.NotContains(t, src.Format(unpaid), "Bounty:")
+}
@@ -0,0 +1,100 @@
+package medium
+
+import (
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for Medium articles.
+const Name = "medium"
+
+// Kinds of Medium feeds reported by Source.Kind.
+const (
+	KindTag         = "medium-tag"
+	KindUser        = "medium-user"
+	KindPublication = "medium-publication"
+)
+
+// Source handles Medium tag, user and publication feeds.
+type Source struct{}
+
+func init() {
+	source.Register(Source{})
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match reports whether the feed is served by Medium.
+func (Source) Match(feedURL string) bool {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return false
+	}
+
+	return utils.IsMediumHost(parsed.Hostname())
+}
+
+// Kinds lists the kinds of Medium feeds.
+func (Source) Kinds() []string {
+	return []string{KindTag, KindUser, KindPublication}
+}
+
+// Kind tells tag feeds (medium.com/feed/tag/...) and user feeds (medium.com/feed/@user or user.medium.com)
+// from publication feeds, which are served at medium.com/feed/<publication> or on their own domain.
+func (Source) Kind(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return KindPublication
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	switch {
+	case host == "medium.com" && strings.HasPrefix(parsed.Path, "/feed/tag/"):
+		return KindTag
+	case host == "medium.com" && (strings.HasPrefix(parsed.Path, "/feed/@") || strings.HasPrefix(parsed.Path, "/@")):
+		return KindUser
+	case strings.HasSuffix(host, ".medium.com"):
+		return KindUser
+	default:
+		return KindPublication
+	}
+}
+
+// Fetch downloads the RSS feed.
+func (Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	return utils.FetchArticles(feedURL)
+}
+
+// Normalize takes the date gofeed parsed. Medium articles are identified by their
+// https://medium.com/p/<id> GUID.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	article := source.NewArticle(Name, feedURL, item)
+	article.URL = item.GUID
+	article.Published = source.PublishedAt(item)
+	return article, nil
+}
+
+// Format checks whether the article is premium and, if it is, links to the freedium mirror instead.
+func (Source) Format(article *source.Article) string {
+	// Check if the URL starts with "https://medium.com"
+	if strings.HasPrefix(article.URL, "https://medium.com") {
+		premium, err := isPremium(article.URL)
+		if err != nil {
+			log.Printf("Error checking premium status for URL %s: %v. Skipping URL.", article.URL, err)
+		} else if premium {
+			// If the article is premium, link to the mirror instead
+			article.Premium = true
+			article.MirrorURL = strings.Replace(article.URL, "https://medium.com", "https://freedium.cfd", 1)
+		}
+	}
+
+	return source.FormatMessage(article)
+}
@@ -0,0 +1,25 @@
+package medium
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKind checks that tag, user and publication feeds are told apart.
+func TestKind(t *testing.T) {
+	cases := map[string]string{
+		"https://medium.com/feed/tag/bug-bounty": KindTag,
+		"https://medium.com/feed/@NahamSec":      KindUser,
+		"https://medium.com/@bugh4nter/feed":     KindUser,
+		"https://vickieli.medium.com/feed":       KindUser,
+		"https://medium.com/feed/intigriti":      KindPublication,
+		"https://infosecwriteups.com/feed":       KindPublication,
+	}
+
+	src := Source{}
+	for feedURL, expected := range cases {
+		assert.True(t, src.Match(feedURL), feedURL)
+		assert.Equal(t, expected, src.Kind(feedURL), feedURL)
+	}
+}
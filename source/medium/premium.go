@@ -0,0 +1,59 @@
+package medium
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// isPremium loads the article in a headless browser and looks for the member-only markers.
+func isPremium(url string) (bool, error) {
+	// Custom user agent and allocator options
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/91.0.4472.124 Safari/537.36"),
+		chromedp.Flag("no-sandbox", true),
+	)
+
+	// Create a new context with the allocator
+	ctx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	defer cancel()
+
+	// Create a new browser context
+	ctx, cancel = chromedp.NewContext(ctx)
+	defer cancel()
+
+	// Set a timeout for the entire operation
+	ctx, cancel = context.WithTimeout(ctx, 60*time.Second) // Extended timeout
+	defer cancel()
+
+	var isPremium bool
+
+	// Run the browser tasks
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(url),
+		chromedp.WaitReady("body"), // Wait for the body to load
+		chromedp.Evaluate(`document.querySelectorAll('[aria-label="Close"]').forEach(btn => btn.click());`, nil), // Close popups
+		chromedp.Evaluate(`{
+			const xpathCheck = document.evaluate(
+				'//*[contains(text(), "Member-only story")]',
+				document,
+				null,
+				XPathResult.ANY_TYPE,
+				null
+			);
+			const hasMemberText = xpathCheck.iterateNext() !== null;
+
+			const hasGoldenStar = document.querySelector('svg[fill="#FFC017"]') !== null;
+
+			hasMemberText || hasGoldenStar;
+		}`, &isPremium),
+	)
+
+	if err != nil {
+		return false, fmt.Errorf("error checking premium status for %s: %v", url, err)
+	}
+
+	return isPremium, nil
+}
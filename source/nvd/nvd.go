@@ -0,0 +1,412 @@
+package nvd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/config"
+	"writeup-finder.go/global"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for CVEs.
+const Name = "nvd"
+
+// DefaultThread is the thread variable of the built-in filter used when no topic declares a cve filter.
+const DefaultThread = "CVE_THREAD_ID"
+
+// Lookback is how far back CVEs are requested from the API when the feed URL has no date range of its own.
+const Lookback = 7 * 24 * time.Hour
+
+// dateLayout is the layout of the published dates of NVD records, which are in UTC.
+const dateLayout = "2006-01-02T15:04:05.000"
+
+// ExcerptLength is the maximum number of characters of the description included in a message.
+const ExcerptLength = 300
+
+// maxReferences is the number of references listed in a message.
+const maxReferences = 3
+
+// Source handles the NVD CVE API 2.0. The data is read from the feed URL, or from NVD_FEED,
+// which may be another URL or the path of a local mirror file in the same JSON 2.0 format.
+type Source struct{}
+
+func init() {
+	source.Register(Source{})
+}
+
+// cveResponse is the part of an NVD CVE API 2.0 response the source reads.
+type cveResponse struct {
+	Vulnerabilities []struct {
+		CVE cve `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+// cve is one CVE record.
+type cve struct {
+	ID           string `json:"id"`
+	Published    string `json:"published"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics struct {
+		V40 []metric `json:"cvssMetricV40"`
+		V31 []metric `json:"cvssMetricV31"`
+		V30 []metric `json:"cvssMetricV30"`
+	} `json:"metrics"`
+	Weaknesses []struct {
+		Description []struct {
+			Value string `json:"value"`
+		} `json:"description"`
+	} `json:"weaknesses"`
+	Configurations []struct {
+		Nodes []struct {
+			CPEMatch []struct {
+				Vulnerable bool   `json:"vulnerable"`
+				Criteria   string `json:"criteria"`
+			} `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+}
+
+// metric is one CVSS score given to a CVE.
+type metric struct {
+	Type     string `json:"type"`
+	CVSSData struct {
+		BaseScore    float64 `json:"baseScore"`
+		BaseSeverity string  `json:"baseSeverity"`
+	} `json:"cvssData"`
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match reports whether the feed entry is the NVD CVE API.
+func (Source) Match(feedURL string) bool {
+	host := source.Host(feedURL)
+	return host == "nvd.nist.gov" || strings.HasSuffix(host, ".nvd.nist.gov")
+}
+
+// Fetch reads the CVEs published during the last Lookback from the API, or the whole mirror file,
+// and keeps the ones matching a topic filter. Nothing is requested when there is no filter.
+func (s Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	if len(Filters()) == 0 {
+		return nil, nil
+	}
+
+	location := config.LookupOr("NVD_FEED", feedURL)
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		file, err := os.Open(location)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return s.Parse(file)
+	}
+
+	req, err := http.NewRequest("GET", requestURL(location, time.Now()), nil)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey := config.Lookup("NVD_API_KEY"); apiKey != "" {
+		req.Header.Set("apiKey", apiKey)
+	}
+
+	resp, err := utils.FeedClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP error: %s, status code: %d", resp.Status, resp.StatusCode)
+	}
+	return s.Parse(resp.Body)
+}
+
+// Parse reads CVE API 2.0 data and maps every CVE matching at least one topic filter to a feed item.
+// The score, severity, weaknesses, products, references and matching threads are kept in the item's Custom map.
+func (Source) Parse(r io.Reader) ([]*gofeed.Item, error) {
+	var response cveResponse
+	if err := json.NewDecoder(r).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	filters := Filters()
+	var items []*gofeed.Item
+	for _, vulnerability := range response.Vulnerabilities {
+		record := vulnerability.CVE
+		score, severity := record.score()
+		products := record.products()
+		description := record.description()
+
+		var threads []string
+		for _, filter := range filters {
+			if filter.Matches(score, severity, products, description) {
+				threads = append(threads, filter.Thread)
+			}
+		}
+		if len(threads) == 0 {
+			continue
+		}
+
+		link := "https://nvd.nist.gov/vuln/detail/" + record.ID
+		item := &gofeed.Item{
+			Title:       record.ID,
+			Link:        link,
+			GUID:        link,
+			Published:   record.Published,
+			Description: description,
+			Categories:  record.weaknesses(),
+			Custom: map[string]string{
+				"score":      strconv.FormatFloat(score, 'f', 1, 64),
+				"severity":   severity,
+				"products":   strings.Join(products, ","),
+				"references": strings.Join(record.references(), "\n"),
+				"threads":    strings.Join(threads, ","),
+			},
+		}
+		if published, err := time.Parse(dateLayout, record.Published); err == nil {
+			item.PublishedParsed = &published
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Normalize identifies CVEs by their NVD page and sends them to every topic whose filter they matched.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	article := source.NewArticle(Name, feedURL, item)
+	article.URL = item.Link
+	article.Published = source.PublishedAt(item)
+	if threads := strings.Split(item.Custom["threads"], ","); threads[0] != "" {
+		article.Thread = threads[0]
+		article.ExtraThreads = threads[1:]
+	}
+	return article, nil
+}
+
+// Format builds a message with the CVSS score, the weaknesses, the description and the first references.
+func (Source) Format(article *source.Article) string {
+	custom := map[string]string{}
+	if article.Item != nil {
+		custom = article.Item.Custom
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\u25BA %s (%s %s)\n", article.Title, custom["severity"], custom["score"])
+	if len(article.Categories) > 0 {
+		fmt.Fprintf(&b, "Weakness: %s\n", strings.Join(article.Categories, ", "))
+	}
+	if custom["products"] != "" {
+		fmt.Fprintf(&b, "Products: %s\n", strings.ReplaceAll(custom["products"], ",", ", "))
+	}
+	fmt.Fprintf(&b, "Published: %s\n", article.Published.Format(global.DateFormat))
+	if description := source.Excerpt(article.Description, ExcerptLength); description != "" {
+		fmt.Fprintf(&b, "\n%s\n", description)
+	}
+	if custom["references"] != "" {
+		b.WriteString("\nReferences:\n")
+		for _, reference := range strings.Split(custom["references"], "\n") {
+			fmt.Fprintf(&b, "- %s\n", reference)
+		}
+	}
+	fmt.Fprintf(&b, "\nLink: %s", article.Link())
+	return b.String()
+}
+
+// Filter is the cve filter of one topic.
+type Filter struct {
+	Thread string
+	config.CVEFilter
+}
+
+// Filters returns the cve filters declared by the topics of the config file. When there are none,
+// DefaultThread receives critical and high CVEs of the comma-separated NVD_PRODUCTS watchlist,
+// and without a watchlist there is no filter, so no CVE is posted.
+func Filters() []Filter {
+	var filters []Filter
+	for _, topic := range config.Current().Topics {
+		if topic.CVE != nil {
+			filters = append(filters, Filter{Thread: topic.EnvName(), CVEFilter: *topic.CVE})
+		}
+	}
+	if len(filters) > 0 {
+		return filters
+	}
+
+	var products []string
+	for _, product := range strings.Split(config.Lookup("NVD_PRODUCTS"), ",") {
+		if product = strings.TrimSpace(product); product != "" {
+			products = append(products, product)
+		}
+	}
+	if len(products) == 0 {
+		return nil
+	}
+	return []Filter{{
+		Thread:    DefaultThread,
+		CVEFilter: config.CVEFilter{Severities: []string{"CRITICAL", "HIGH"}, Products: products},
+	}}
+}
+
+// Matches reports whether a CVE passes the filter. Watchlist entries are compared with the vendor and
+// product of the CVE's CPEs; CVEs that are not analysed yet have no CPEs and must name both the vendor and
+// the product in their description.
+func (f Filter) Matches(score float64, severity string, products []string, description string) bool {
+	if score < f.MinScore {
+		return false
+	}
+	if len(f.Severities) > 0 && !containsFold(f.Severities, severity) {
+		return false
+	}
+	if len(f.Products) == 0 {
+		return true
+	}
+
+	for _, watched := range f.Products {
+		watched = strings.ToLower(watched)
+		for _, product := range products {
+			vendor, name, _ := strings.Cut(product, ":")
+			if watched == product || watched == vendor || watched == name {
+				return true
+			}
+		}
+		if len(products) == 0 && describes(description, watched) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestURL adds a publication date range covering the last Lookback to an API URL that has none.
+func requestURL(apiURL string, now time.Time) string {
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		return apiURL
+	}
+
+	query := parsed.Query()
+	if query.Get("pubStartDate") == "" {
+		query.Set("pubStartDate", now.Add(-Lookback).UTC().Format(dateLayout)+"Z")
+		query.Set("pubEndDate", now.UTC().Format(dateLayout)+"Z")
+		parsed.RawQuery = query.Encode()
+	}
+	return parsed.String()
+}
+
+// score returns the primary CVSS base score and severity of the CVE, preferring the newest CVSS version.
+func (c cve) score() (float64, string) {
+	for _, metrics := range [][]metric{c.Metrics.V40, c.Metrics.V31, c.Metrics.V30} {
+		for _, m := range metrics {
+			if m.Type == "Primary" {
+				return m.CVSSData.BaseScore, m.CVSSData.BaseSeverity
+			}
+		}
+		if len(metrics) > 0 {
+			return metrics[0].CVSSData.BaseScore, metrics[0].CVSSData.BaseSeverity
+		}
+	}
+	return 0, ""
+}
+
+// description returns the English description of the CVE.
+func (c cve) description() string {
+	for _, d := range c.Descriptions {
+		if d.Lang == "en" {
+			return d.Value
+		}
+	}
+	return ""
+}
+
+// weaknesses returns the distinct CWE IDs of the CVE.
+func (c cve) weaknesses() []string {
+	var cwes []string
+	for _, weakness := range c.Weaknesses {
+		for _, d := range weakness.Description {
+			if strings.HasPrefix(d.Value, "CWE-") && !containsFold(cwes, d.Value) {
+				cwes = append(cwes, d.Value)
+			}
+		}
+	}
+	return cwes
+}
+
+// products returns the distinct "vendor:product" pairs of the vulnerable CPEs of the CVE, in lower case.
+func (c cve) products() []string {
+	var products []string
+	for _, configuration := range c.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CPEMatch {
+				// cpe:2.3:part:vendor:product:version:...
+				parts := strings.Split(match.Criteria, ":")
+				if !match.Vulnerable || len(parts) < 5 {
+					continue
+				}
+				product := strings.ToLower(parts[3] + ":" + parts[4])
+				if !containsFold(products, product) {
+					products = append(products, product)
+				}
+			}
+		}
+	}
+	return products
+}
+
+// references returns the first reference URLs of the CVE.
+func (c cve) references() []string {
+	var references []string
+	for _, reference := range c.References {
+		if len(references) == maxReferences {
+			break
+		}
+		references = append(references, reference.URL)
+	}
+	return references
+}
+
+// describes reports whether a description names every part of a watchlist entry, vendor and product, as
+// whole words. Underscores in CPE names stand for spaces.
+func describes(description, watched string) bool {
+	for _, part := range strings.Split(watched, ":") {
+		words := strings.Fields(strings.ReplaceAll(part, "_", " "))
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		pattern := `(?i)\b` + strings.Join(words, `\s+`) + `\b`
+		if !regexp.MustCompile(pattern).MatchString(description) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package nvd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"writeup-finder.go/config"
+)
+
+// TestFetchMirrorFile reads a local mirror file with the built-in filter and checks which CVEs are kept
+// and how they are presented.
+func TestFetchMirrorFile(t *testing.T) {
+	t.Setenv("NVD_FEED", "testdata/cves.json")
+	t.Setenv("NVD_PRODUCTS", "gitlab, fortinet:fortios")
+
+	feedURL := "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	src := Source{}
+	assert.True(t, src.Match(feedURL))
+
+	items, err := src.Fetch(feedURL)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2, "nginx is not watched and the GitLab open redirect is only medium") {
+		assert.Equal(t, "CVE-2025-0475", items[0].Title)
+		assert.Equal(t, "CVE-2025-24472", items[1].Title, "unanalysed CVEs are matched on their description")
+	}
+
+	article, err := src.Normalize(feedURL, items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2025-0475", article.URL)
+	assert.Equal(t, DefaultThread, article.Thread)
+	assert.Equal(t, []string{"CWE-79"}, article.Categories)
+
+	message := src.Format(article)
+	assert.Contains(t, message, "CVE-2025-0475 (HIGH 8.7)\n")
+	assert.Contains(t, message, "Products: gitlab:gitlab\n")
+	assert.Contains(t, message, "- https://hackerone.com/reports/2967450\n")
+	assert.NotContains(t, message, "https://example.com/advisory", "only the first references are listed")
+}
+
+// TestFetchAPI checks that API requests are limited to recently published CVEs
+// and that nothing is requested without a watchlist.
+func TestFetchAPI(t *testing.T) {
+	var start string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start = r.URL.Query().Get("pubStartDate")
+		http.ServeFile(w, r, "testdata/cves.json")
+	}))
+	defer server.Close()
+	t.Setenv("NVD_FEED", server.URL+"/rest/json/cves/2.0")
+	t.Setenv("NVD_PRODUCTS", "")
+
+	feedURL := "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	items, err := Source{}.Fetch(feedURL)
+	assert.NoError(t, err)
+	assert.Empty(t, items, "without a watchlist no CVE is posted")
+	assert.Zero(t, requests)
+
+	t.Setenv("NVD_PRODUCTS", "gitlab")
+	items, err = Source{}.Fetch(feedURL)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, start)
+	assert.Len(t, items, 1, "only the critical and high GitLab CVEs are kept")
+}
+
+// TestFilterMatches checks the severity, score and watchlist rules of a topic filter.
+func TestFilterMatches(t *testing.T) {
+	filter := Filter{Thread: "CVE_THREAD_ID", CVEFilter: config.CVEFilter{
+		Severities: []string{"critical"},
+		Products:   []string{"apache:http_server"},
+		MinScore:   9,
+	}}
+
+	assert.True(t, filter.Matches(9.8, "CRITICAL", []string{"apache:http_server"}, ""))
+	assert.False(t, filter.Matches(9.8, "CRITICAL", []string{"apache:tomcat"}, ""))
+	assert.False(t, filter.Matches(8.8, "HIGH", []string{"apache:http_server"}, ""))
+	assert.True(t, filter.Matches(9.1, "CRITICAL", nil, "A flaw in Apache HTTP Server mod_proxy"))
+	assert.False(t, filter.Matches(9.1, "CRITICAL", nil, "A flaw in the Acme HTTP server firmware"))
+	assert.False(t, filter.Matches(9.1, "CRITICAL", nil, "Apache HTTP Servers are not affected, only Tomcat"))
+	assert.False(t, filter.Matches(9.1, "CRITICAL", nil, "A flaw in apachehttp server"))
+}
@@ -0,0 +1,191 @@
+package reddit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/config"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for Reddit posts.
+const Name = "reddit"
+
+// DefaultMinScore is the score a post needs before it is shared, unless REDDIT_MIN_SCORE says otherwise.
+const DefaultMinScore = 10
+
+// UserAgent identifies the finder to Reddit, which rejects requests sent with generic browser agents.
+const UserAgent = "writeup-finder/1.0 (+https://github.com/blackvoidx/writeup-finder)"
+
+// Source handles subreddit listings, in their JSON (/r/netsec/new.json) or RSS (/r/netsec/.rss) form.
+type Source struct{}
+
+func init() {
+	source.Register(Source{})
+}
+
+// listing is the part of a subreddit JSON listing the source reads.
+type listing struct {
+	Data struct {
+		Children []struct {
+			Data post `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// post is one submission of a listing.
+type post struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	URL        string  `json:"url"`
+	Permalink  string  `json:"permalink"`
+	Author     string  `json:"author"`
+	Subreddit  string  `json:"subreddit"`
+	Flair      string  `json:"link_flair_text"`
+	Score      int     `json:"score"`
+	Comments   int     `json:"num_comments"`
+	IsSelf     bool    `json:"is_self"`
+	CreatedUTC float64 `json:"created_utc"`
+	SelfText   string  `json:"selftext"`
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match reports whether the feed is a subreddit listing.
+func (Source) Match(feedURL string) bool {
+	host := source.Host(feedURL)
+	return host == "reddit.com" || strings.HasSuffix(host, ".reddit.com")
+}
+
+// Fetch downloads the listing. JSON listings are filtered by MinScore; RSS listings carry no score
+// and are returned as they are.
+func (s Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	req, err := http.NewRequest("GET", feedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := utils.FeedClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP error: %s, status code: %d", resp.Status, resp.StatusCode)
+	}
+
+	if !isJSON(feedURL) {
+		feed, err := gofeed.NewParser().Parse(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return feed.Items, nil
+	}
+	return s.Parse(resp.Body)
+}
+
+// Parse reads a JSON listing and maps every post scoring at least MinScore to a feed item.
+// The item links to the post's outbound URL and keeps the comments page as its GUID.
+func (Source) Parse(r io.Reader) ([]*gofeed.Item, error) {
+	var l listing
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, err
+	}
+
+	minScore := MinScore()
+	var items []*gofeed.Item
+	for _, child := range l.Data.Children {
+		p := child.Data
+		if p.Score < minScore {
+			continue
+		}
+
+		created := time.Unix(int64(p.CreatedUTC), 0).UTC()
+		item := &gofeed.Item{
+			Title:           p.Title,
+			Link:            p.URL,
+			GUID:            "https://www.reddit.com" + p.Permalink,
+			Description:     p.SelfText,
+			Published:       created.Format(time.RFC1123Z),
+			PublishedParsed: &created,
+			Author:          &gofeed.Person{Name: p.Author},
+			Custom: map[string]string{
+				"subreddit": p.Subreddit,
+				"score":     strconv.Itoa(p.Score),
+				"comments":  strconv.Itoa(p.Comments),
+			},
+		}
+		if p.IsSelf {
+			item.Link = item.GUID
+		}
+		if p.Flair != "" {
+			item.Categories = []string{p.Flair}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// Normalize keeps the outbound link of the post. Its canonical form matches the URL the
+// article has in its own feed, so a Medium article seen on both is only sent once.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	article := source.NewArticle(Name, feedURL, item)
+	article.URL = outboundLink(item)
+	if article.URL == "" {
+		return nil, fmt.Errorf("no link in post %q", item.Title)
+	}
+	article.Published = source.PublishedAt(item)
+	return article, nil
+}
+
+// Format builds the default message followed by the score and the discussion link.
+func (Source) Format(article *source.Article) string {
+	message := source.FormatMessage(article)
+	if article.Item == nil || article.Item.GUID == article.URL {
+		return message
+	}
+	if score := article.Item.Custom["score"]; score != "" {
+		message += fmt.Sprintf("\nScore: %s (%s comments)", score, article.Item.Custom["comments"])
+	}
+	return message + "\nDiscussion: " + article.Item.GUID
+}
+
+// MinScore returns the minimum score from REDDIT_MIN_SCORE, or DefaultMinScore when it is unset or invalid.
+func MinScore() int {
+	if score, err := strconv.Atoi(config.Lookup("REDDIT_MIN_SCORE")); err == nil {
+		return score
+	}
+	return DefaultMinScore
+}
+
+// rssLinkPattern finds the "[link]" anchor Reddit RSS entries use for the outbound URL.
+var rssLinkPattern = regexp.MustCompile(`<a href="([^"]+)">\[link\]</a>`)
+
+// outboundLink returns the URL the post shares: the item link for JSON listings,
+// or the "[link]" anchor of the content for RSS listings whose item link is the comments page.
+func outboundLink(item *gofeed.Item) string {
+	if m := rssLinkPattern.FindStringSubmatch(item.Content); m != nil {
+		return strings.ReplaceAll(m[1], "&amp;", "&")
+	}
+	return item.Link
+}
+
+// isJSON reports whether the listing URL asks for the JSON form.
+func isJSON(feedURL string) bool {
+	parsed, err := url.Parse(feedURL)
+	return err == nil && strings.HasSuffix(parsed.Path, ".json")
+}
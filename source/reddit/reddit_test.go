@@ -0,0 +1,45 @@
+package reddit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFetchListing serves a recorded r/netsec listing and checks the score threshold,
+// the outbound links and the Medium dedupe key.
+func TestFetchListing(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	feedURL := server.URL + "/netsec_new.json"
+	src := Source{}
+
+	items, err := src.Fetch(feedURL)
+	assert.NoError(t, err)
+	assert.Len(t, items, 3, "the post scoring 3 is below DefaultMinScore")
+
+	medium, err := src.Normalize(feedURL, items[0])
+	assert.NoError(t, err)
+	medium.SetDedupeKey()
+	assert.Equal(t, "https://infosecwriteups.com/exploiting-a-blind-ssrf-in-a-pdf-renderer-4f2c9a1b7d3e?source=rss", medium.URL)
+	assert.Equal(t, "url:https://medium.com/p/4f2c9a1b7d3e", medium.DedupeKey)
+	assert.Equal(t, "h4x0r_jane", medium.Author)
+	assert.Equal(t, []string{"Web Security"}, medium.Categories)
+	assert.Equal(t, int64(1738661400), medium.Published.Unix())
+
+	message := src.Format(medium)
+	assert.Contains(t, message, "Score: 152 (18 comments)")
+	assert.Contains(t, message, "Discussion: https://www.reddit.com/r/netsec/comments/1ihb2rq/")
+
+	blog, err := src.Normalize(feedURL, items[1])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://blog.example.com/7zip-motw-bypass", blog.URL)
+
+	self, err := src.Normalize(feedURL, items[2])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://www.reddit.com/r/netsec/comments/1ih8c0m/q1_2025_hiring_thread/", self.URL)
+	assert.NotContains(t, src.Format(self), "Discussion:")
+}
@@ -0,0 +1,51 @@
+package rss
+
+import (
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for articles from generic feeds.
+const Name = "rss"
+
+// Source handles any RSS or Atom feed that no dedicated source matches.
+type Source struct{}
+
+func init() {
+	source.RegisterFallback(Source{})
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match accepts every feed, since this is the fallback source.
+func (Source) Match(feedURL string) bool {
+	return true
+}
+
+// Fetch downloads and parses the feed.
+func (Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	return utils.FetchArticles(feedURL)
+}
+
+// Normalize takes the date gofeed parsed. Articles are identified by their GUID,
+// or by their link when the GUID is not a URL.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	article := source.NewArticle(Name, feedURL, item)
+	article.URL = item.GUID
+	if !strings.HasPrefix(article.URL, "http") {
+		article.URL = item.Link
+	}
+	article.Published = source.PublishedAt(item)
+	return article, nil
+}
+
+// Format builds the default message.
+func (Source) Format(article *source.Article) string {
+	return source.FormatMessage(article)
+}
@@ -0,0 +1,57 @@
+package rss
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJSONFeed fetches a JSON Feed 1.1 document and checks the dates, the inherited author and the undated item.
+func TestJSONFeed(t *testing.T) {
+	var accept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/feed+json")
+		http.ServeFile(w, r, "testdata/feed.json")
+	}))
+	defer server.Close()
+
+	src := Source{}
+	items, err := src.Fetch(server.URL + "/feed.json")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(accept, "application/feed+json"))
+	assert.Len(t, items, 2)
+
+	article, err := src.Normalize(server.URL, items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://blog.example.com/2025/02/idor-in-invoices", article.URL)
+	assert.True(t, time.Date(2025, 2, 4, 8, 30, 0, 0, time.UTC).Equal(article.Published))
+	assert.Equal(t, "Jane Doe", article.Author)
+	assert.Equal(t, []string{"idor", "api"}, article.Categories)
+
+	undated, err := src.Normalize(server.URL, items[1])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://blog.example.com/notes/42", undated.URL, "non-URL IDs fall back to the item URL")
+	assert.True(t, undated.Published.IsZero(), "undated items are dated by the handler when first seen")
+}
+
+// TestAtomFeed fetches an Atom feed whose entries only have an update date.
+func TestAtomFeed(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	src := Source{}
+	items, err := src.Fetch(server.URL + "/atom.xml")
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+
+	article, err := src.Normalize(server.URL, items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://research.example.com/waf-unicode", article.URL)
+	assert.True(t, time.Date(2025, 2, 4, 18, 30, 2, 0, time.UTC).Equal(article.Published))
+	assert.Equal(t, "Example Research Team", article.Author)
+}
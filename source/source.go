@@ -0,0 +1,291 @@
+package source
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/utils"
+)
+
+// Source is one kind of feed, such as Medium or YouTube.
+// Each implementation decides which feed URLs it handles, how they are fetched,
+// how their items are turned into articles and how articles are presented.
+type Source interface {
+	// Name is the source type recorded with every article, e.g. "medium".
+	Name() string
+	// Match reports whether the source handles the given feed URL.
+	Match(feedURL string) bool
+	// Fetch downloads the feed and returns its items.
+	Fetch(feedURL string) ([]*gofeed.Item, error)
+	// Normalize turns a feed item into an article, parsing its date and picking its URL.
+	// The handler then derives the dedupe key from the canonical form of the URL.
+	Normalize(feedURL string, item *gofeed.Item) (*Article, error)
+	// Format builds the Telegram message for an article.
+	Format(article *Article) string
+}
+
+// Parser is implemented by sources whose feeds are not RSS, Atom or JSON Feed documents,
+// so a saved copy of the feed can be read without fetching it.
+type Parser interface {
+	Parse(r io.Reader) ([]*gofeed.Item, error)
+}
+
+// Classifier is implemented by sources that tell several kinds of feeds apart, such as Medium tag and user feeds.
+type Classifier interface {
+	// Kinds lists the kinds of feeds the source handles, in display order.
+	Kinds() []string
+	// Kind returns the kind of a feed the source handles.
+	Kind(feedURL string) string
+}
+
+// Article is a feed item normalized by a Source.
+type Article struct {
+	Title         string
+	URL           string
+	GUID          string
+	Published     time.Time
+	PublishedText string
+	Author        string
+	Categories    []string
+	Description   string
+	Content       string
+	FeedURL       string
+	SourceType    string
+	DedupeKey     string
+	Thread        string   // Thread variable that bypasses keyword matching, e.g. YOUTUBE_THREAD_ID
+	ExtraThreads  []string // Thread variables the article is posted to as well, e.g. MONEY_THREAD_ID
+	Premium       bool
+	MirrorURL     string
+	Item          *gofeed.Item
+}
+
+// Link returns the URL shared in messages: the mirror for premium articles, the original otherwise.
+func (a *Article) Link() string {
+	if a.MirrorURL != "" {
+		return a.MirrorURL
+	}
+	return a.URL
+}
+
+var (
+	registry   []Source
+	fallback   Source
+	registryMu sync.RWMutex
+)
+
+// Register adds a source to the registry. Sources are matched in registration order.
+func Register(s Source) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, s)
+}
+
+// RegisterFallback sets the source used for feed URLs that no registered source matches.
+func RegisterFallback(s Source) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	fallback = s
+}
+
+// For returns the source that handles the feed URL, or the fallback source if none matches.
+func For(feedURL string) Source {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, s := range registry {
+		if s.Match(feedURL) {
+			return s
+		}
+	}
+	return fallback
+}
+
+// Kind returns the kind of feed behind a URL: the kind reported by its source, or the name of the source.
+func Kind(feedURL string) string {
+	src := For(feedURL)
+	if classifier, ok := src.(Classifier); ok {
+		return classifier.Kind(feedURL)
+	}
+	return src.Name()
+}
+
+// Kinds returns every kind of feed in display order: the kinds of each source in registration order,
+// followed by the fallback source.
+func Kinds() []string {
+	var kinds []string
+	for _, src := range All() {
+		if classifier, ok := src.(Classifier); ok {
+			kinds = append(kinds, classifier.Kinds()...)
+		} else {
+			kinds = append(kinds, src.Name())
+		}
+	}
+	return kinds
+}
+
+// RateLimitKey groups the feeds that share a request budget: the feeds of a platform share the name of
+// the source that handles them, and feeds only the fallback source reads are limited per host.
+func RateLimitKey(feedURL string) string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, s := range registry {
+		if s.Match(feedURL) {
+			return s.Name()
+		}
+	}
+	return Host(feedURL)
+}
+
+// All returns the registered sources followed by the fallback source.
+func All() []Source {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	sources := append([]Source{}, registry...)
+	if fallback != nil {
+		sources = append(sources, fallback)
+	}
+	return sources
+}
+
+// URLKey is the dedupe key of an article with the given canonical URL.
+func URLKey(url string) string {
+	return "url:" + url
+}
+
+// SetDedupeKey keys the article by the canonical form of its URL, so the same article reached through
+// another feed, a tracking link or the freedium mirror is only sent once. The URL itself is left as is.
+func (a *Article) SetDedupeKey() {
+	a.DedupeKey = URLKey(utils.CanonicalizeURL(a.URL))
+}
+
+// KeywordText returns the fields of the article that keyword rules are matched against, as plain text.
+func (a *Article) KeywordText() utils.ArticleText {
+	return utils.ArticleText{
+		Title:       a.Title,
+		Description: PlainText(a.Description),
+		Content:     PlainText(a.Content),
+		Categories:  a.Categories,
+	}
+}
+
+// FingerprintLength is the number of description characters included in an article fingerprint.
+const FingerprintLength = 300
+
+// Fingerprint returns the simhash of the title and the start of the description, or of the content when
+// the item has no description. Cross-posts keep close fingerprints even when their title is retouched.
+func (a *Article) Fingerprint() uint64 {
+	text := a.Description
+	if text == "" {
+		text = a.Content
+	}
+	return utils.Simhash(a.Title + " " + Excerpt(text, FingerprintLength))
+}
+
+// Host returns the lowercased host name of a feed URL, or an empty string if it cannot be parsed.
+func Host(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// PublishedAt returns the publication date gofeed parsed for the item, falling back to its update date.
+// gofeed understands RFC1123, RFC3339 and many other layouts, in RSS, Atom and JSON Feed documents alike.
+// It returns the zero time when the item has no date at all; the handler then uses the time the item was first seen.
+func PublishedAt(item *gofeed.Item) time.Time {
+	switch {
+	case item.PublishedParsed != nil:
+		return *item.PublishedParsed
+	case item.UpdatedParsed != nil:
+		return *item.UpdatedParsed
+	default:
+		return time.Time{}
+	}
+}
+
+// CanonicalURL returns the item's permalink, falling back to its GUID when the GUID is a URL.
+// Blogging platforms put the canonical post URL in the link, while their GUIDs are often opaque IDs.
+func CanonicalURL(item *gofeed.Item) string {
+	if link := strings.TrimSpace(item.Link); link != "" {
+		return link
+	}
+	if strings.HasPrefix(item.GUID, "http") {
+		return item.GUID
+	}
+	return ""
+}
+
+// FormatMessage builds the default message shared by most sources.
+func FormatMessage(article *Article) string {
+	return fmt.Sprintf("\u25BA %s\nPublished: %s\nLink: %s", article.Title, article.PublishedText, article.Link())
+}
+
+// NewArticle fills the fields every source takes from a gofeed item the same way.
+// The caller still sets the URL, date and dedupe key.
+func NewArticle(sourceType, feedURL string, item *gofeed.Item) *Article {
+	author := ""
+	if item.Author != nil {
+		author = item.Author.Name
+	} else if len(item.Authors) > 0 {
+		author = item.Authors[0].Name
+	}
+
+	return &Article{
+		Title:         item.Title,
+		GUID:          item.GUID,
+		PublishedText: item.Published,
+		Author:        author,
+		Categories:    item.Categories,
+		Description:   item.Description,
+		Content:       item.Content,
+		FeedURL:       feedURL,
+		SourceType:    sourceType,
+		Item:          item,
+	}
+}
+
+// NewPost normalizes a blog post the way the blogging platform sources share: the date comes from
+// PublishedAt and the URL from CanonicalURL.
+func NewPost(sourceType, feedURL string, item *gofeed.Item) (*Article, error) {
+	canonical := CanonicalURL(item)
+	if canonical == "" {
+		return nil, fmt.Errorf("no link in item %q", item.Title)
+	}
+
+	article := NewArticle(sourceType, feedURL, item)
+	article.URL = canonical
+	article.Published = PublishedAt(item)
+	return article, nil
+}
+
+var (
+	tagPattern   = regexp.MustCompile(`<[^>]*>`)
+	spacePattern = regexp.MustCompile(`\s+`)
+)
+
+// PlainText turns HTML or plain text into a single line of plain text.
+func PlainText(content string) string {
+	text := html.UnescapeString(tagPattern.ReplaceAllString(content, " "))
+	return strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+}
+
+// Excerpt turns HTML or plain text into a single line of plain text of at most limit characters.
+func Excerpt(content string, limit int) string {
+	text := PlainText(content)
+
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
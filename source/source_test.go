@@ -0,0 +1,59 @@
+package source
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+)
+
+// ghostFeed is a trimmed Ghost feed: the GUID is an opaque ID, the author comes from dc:creator
+// and the date is not in RFC1123 layout.
+const ghostFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<channel>
+<title>Example</title>
+<item>
+<title><![CDATA[SSRF in the PDF renderer]]></title>
+<link>https://blog.example.com/ssrf-pdf-renderer/</link>
+<guid isPermaLink="false">65a1f0c2e4b0a1b2c3d4e5f6</guid>
+<category><![CDATA[Bug Bounty]]></category>
+<category><![CDATA[SSRF]]></category>
+<dc:creator><![CDATA[Jane Doe]]></dc:creator>
+<pubDate>Tue, 4 Feb 2025 09:30:00 GMT</pubDate>
+</item>
+</channel>
+</rss>`
+
+// TestNewPost checks that a blog post keeps its canonical link, author, tags and parsed date, and is keyed by its canonical URL.
+func TestNewPost(t *testing.T) {
+	feed, err := gofeed.NewParser().Parse(strings.NewReader(ghostFeed))
+	assert.NoError(t, err)
+
+	article, err := NewPost("ghost", "https://blog.example.com/rss/", feed.Items[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "https://blog.example.com/ssrf-pdf-renderer/", article.URL)
+	assert.Equal(t, "Jane Doe", article.Author)
+	assert.Equal(t, []string{"Bug Bounty", "SSRF"}, article.Categories)
+	assert.True(t, time.Date(2025, 2, 4, 9, 30, 0, 0, time.UTC).Equal(article.Published))
+
+	article.SetDedupeKey()
+	assert.Equal(t, "url:https://blog.example.com/ssrf-pdf-renderer", article.DedupeKey)
+}
+
+// TestPublishedAtFallsBackToUpdated checks that Atom entries without a published date use their update date,
+// and that undated items get the zero time.
+func TestPublishedAtFallsBackToUpdated(t *testing.T) {
+	updated := time.Date(2025, 2, 4, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, updated, PublishedAt(&gofeed.Item{UpdatedParsed: &updated}))
+	assert.True(t, PublishedAt(&gofeed.Item{Title: "undated"}).IsZero())
+}
+
+// TestExcerpt checks that long texts are cut on a character boundary.
+func TestExcerpt(t *testing.T) {
+	assert.Equal(t, "Fixes", Excerpt("<p>Fixes</p>", 10))
+	assert.Equal(t, "ééé…", Excerpt("éééé", 3))
+}
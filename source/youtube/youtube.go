@@ -0,0 +1,52 @@
+package youtube
+
+import (
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"writeup-finder.go/source"
+	"writeup-finder.go/utils"
+)
+
+// Name is the source type recorded for YouTube videos.
+const Name = "youtube"
+
+// Thread is the thread variable every video is posted to, regardless of keywords.
+const Thread = "YOUTUBE_THREAD_ID"
+
+// Source handles YouTube channel feeds.
+type Source struct{}
+
+func init() {
+	source.Register(Source{})
+}
+
+// Name returns the source type.
+func (Source) Name() string {
+	return Name
+}
+
+// Match reports whether the URL is a YouTube RSS feed.
+func (Source) Match(feedURL string) bool {
+	return strings.HasPrefix(feedURL, "https://www.youtube.com/feeds/")
+}
+
+// Fetch downloads and parses the channel's Atom feed with a conditional request.
+func (Source) Fetch(feedURL string) ([]*gofeed.Item, error) {
+	return utils.FetchArticles(feedURL)
+}
+
+// Normalize takes the date gofeed parsed from the Atom entry. Videos are identified by their watch link
+// and always go to the YouTube thread.
+func (Source) Normalize(feedURL string, item *gofeed.Item) (*source.Article, error) {
+	article := source.NewArticle(Name, feedURL, item)
+	article.URL = item.Link
+	article.Published = source.PublishedAt(item)
+	article.Thread = Thread
+	return article, nil
+}
+
+// Format builds the default message.
+func (Source) Format(article *source.Article) string {
+	return source.FormatMessage(article)
+}
@@ -0,0 +1,79 @@
+package telegram
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"writeup-finder.go/config"
+)
+
+// DefaultAPIURL is the Bot API base URL used when TELEGRAM_API_URL is not set.
+const DefaultAPIURL = "https://api.telegram.org"
+
+// BotUser is the subset of the getMe result used by the doctor command.
+type BotUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	IsBot    bool   `json:"is_bot"`
+}
+
+// Chat is the subset of the getChat result used by the doctor command.
+type Chat struct {
+	ID      int64  `json:"id"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	IsForum bool   `json:"is_forum"`
+}
+
+// apiResponse is the envelope of every Bot API response.
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// APIURL returns the URL of a Bot API method.
+// The base URL can be overridden with TELEGRAM_API_URL, e.g. to point at a local stand-in.
+func APIURL(botToken, method string) string {
+	base := strings.TrimSuffix(config.LookupOr("TELEGRAM_API_URL", DefaultAPIURL), "/")
+	return fmt.Sprintf("%s/bot%s/%s", base, botToken, method)
+}
+
+// GetMe returns the bot behind the token, which proves the token is valid.
+func GetMe(client *http.Client, botToken string) (*BotUser, error) {
+	var user BotUser
+	if err := callAPI(client, botToken, "getMe", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetChat returns the chat with the given ID, which proves the bot can see it.
+func GetChat(client *http.Client, botToken, chatID string) (*Chat, error) {
+	var chat Chat
+	if err := callAPI(client, botToken, "getChat", url.Values{"chat_id": {chatID}}, &chat); err != nil {
+		return nil, err
+	}
+	return &chat, nil
+}
+
+// callAPI calls a Bot API method and decodes its result into result.
+func callAPI(client *http.Client, botToken, method string, params url.Values, result any) error {
+	resp, err := client.PostForm(APIURL(botToken, method), params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s: invalid response (status %d): %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("%s: %s (status %d)", method, envelope.Description, resp.StatusCode)
+	}
+	return json.Unmarshal(envelope.Result, result)
+}
@@ -0,0 +1,66 @@
+package telegram
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newStandIn starts a local Bot API stand-in and points TELEGRAM_API_URL at it.
+func newStandIn(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/botgood-token/getMe":
+			w.Write([]byte(`{"ok":true,"result":{"id":42,"is_bot":true,"username":"writeup_bot"}}`))
+		case "/botgood-token/getChat":
+			if r.FormValue("chat_id") != "-100123" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+				return
+			}
+			w.Write([]byte(`{"ok":true,"result":{"id":-100123,"type":"supergroup","title":"Writeups","is_forum":true}}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"ok":false,"description":"Unauthorized"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("TELEGRAM_API_URL", server.URL)
+	return server
+}
+
+// TestGetMe checks that a valid token returns the bot and an invalid one returns the API error.
+func TestGetMe(t *testing.T) {
+	server := newStandIn(t)
+
+	user, err := GetMe(server.Client(), "good-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "writeup_bot", user.Username)
+
+	_, err = GetMe(server.Client(), "bad-token")
+	assert.ErrorContains(t, err, "Unauthorized")
+}
+
+// TestGetChat checks that the chat details are decoded and unknown chats are reported.
+func TestGetChat(t *testing.T) {
+	server := newStandIn(t)
+
+	chat, err := GetChat(server.Client(), "good-token", "-100123")
+	assert.NoError(t, err)
+	assert.True(t, chat.IsForum)
+	assert.Equal(t, "Writeups", chat.Title)
+
+	_, err = GetChat(server.Client(), "good-token", "-100999")
+	assert.ErrorContains(t, err, "chat not found")
+}
+
+// TestValidateProxyURL checks the accepted and rejected proxy URLs.
+func TestValidateProxyURL(t *testing.T) {
+	assert.NoError(t, ValidateProxyURL("socks5://127.0.0.1:1080"))
+	assert.Error(t, ValidateProxyURL("ftp://127.0.0.1:21"))
+	assert.Error(t, ValidateProxyURL("http://"))
+	assert.Error(t, ValidateProxyURL("http://%zz"))
+}
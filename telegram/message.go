@@ -5,4 +5,10 @@ type TelegramMessage struct {
 	ChatID          string `json:"chat_id"`
 	Text            string `json:"text"`
 	MessageThreadID string `json:"message_thread_id,omitempty"`
+	MessageID       int64  `json:"message_id,omitempty"` // Set when editing a message sent earlier
+}
+
+// SentMessage is the subset of the sendMessage result kept to edit the message later.
+type SentMessage struct {
+	MessageID int64 `json:"message_id"`
 }
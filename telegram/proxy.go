@@ -3,15 +3,15 @@ package telegram
 import (
 	"fmt"
 	"net/url"
-
-	"writeup-finder.go/utils"
 )
 
 // ValidateProxyURL checks if the provided proxy URL is valid and supported.
-// It returns an error if the scheme is unsupported or the hostname is missing.
+// It returns an error if the URL cannot be parsed, the scheme is unsupported or the hostname is missing.
 func ValidateProxyURL(proxyURL string) error {
 	parsedURL, err := url.Parse(proxyURL)
-	utils.HandleError(err, "Error:", true)
+	if err != nil {
+		return fmt.Errorf("invalid proxy URL: %w", err)
+	}
 
 	switch parsedURL.Scheme {
 	case "http", "https", "socks5":
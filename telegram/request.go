@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
@@ -13,7 +14,8 @@ import (
 
 // sendRequest sends an HTTP POST request to the Telegram API.
 // It handles retries for network errors, rate limiting, and unexpected status codes.
-func SendRequest(client *http.Client, apiURL string, jsonData []byte, retryCount *int) error {
+// On success the result of the call is decoded into result, unless it is nil.
+func SendRequest(client *http.Client, apiURL string, jsonData []byte, retryCount *int, result any) error {
 	resp, err := client.Post(apiURL, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
 		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
@@ -27,7 +29,14 @@ func SendRequest(client *http.Client, apiURL string, jsonData []byte, retryCount
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusOK {
-		return nil // Success, no need to retry
+		// Success, no need to retry. A result that cannot be decoded is not worth sending the message again
+		if result != nil {
+			var envelope apiResponse
+			if json.NewDecoder(resp.Body).Decode(&envelope) == nil {
+				json.Unmarshal(envelope.Result, result)
+			}
+		}
+		return nil
 	}
 
 	// Handle rate limiting
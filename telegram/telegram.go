@@ -2,10 +2,13 @@ package telegram
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
+	"os"
+	"strconv"
 	"time"
 
+	"writeup-finder.go/config"
+	"writeup-finder.go/global"
 	"writeup-finder.go/utils"
 )
 
@@ -15,54 +18,128 @@ const (
 	rateLimitBase = 2               // Base multiplier for rate limit backoff
 )
 
-// SendToTelegram sends a message to a Telegram channel using the provided proxy.
-// It handles retries, rate limiting, and thread selection based on the message type (YouTube or keyword-based).
-func SendToTelegram(message string, proxyURL string, title string, isYoutube bool) {
-	botToken := utils.GetEnv("TELEGRAM_BOT_TOKEN")
-	channelID := utils.GetEnv("CHAT_ID")
-	mainThreadID := utils.GetEnv("MAIN_THREAD_ID")
-	youtubeThreadID := utils.GetEnv("YOUTUBE_THREAD_ID")
-
-	var messageThreadID string
-
-	if isYoutube {
-		messageThreadID = youtubeThreadID
-	} else {
-		// Load keywords from the JSON configuration
-		keywords, err := utils.LoadKeywords("data/keywords.json")
-		if err != nil {
-			utils.HandleError(err, "Failed to load keyword patterns", true)
+// DefaultMaxTopics is the number of topics an article can be posted to unless MAX_TOPICS_PER_ARTICLE says otherwise.
+// One keeps multi-route mode off.
+const DefaultMaxTopics = 1
+
+// Route is a thread a message is posted to and the name of the variable it came from.
+type Route struct {
+	ThreadID  string
+	Topic     string
+	Exclusive bool // Set when the topic was picked by an exclusive keyword group and is not shared
+}
+
+// MaxTopics returns the number of topics from MAX_TOPICS_PER_ARTICLE, or DefaultMaxTopics when it is unset or invalid.
+func MaxTopics() int {
+	if topics, err := strconv.Atoi(config.Lookup("MAX_TOPICS_PER_ARTICLE")); err == nil && topics > 0 {
+		return topics
+	}
+	return DefaultMaxTopics
+}
+
+// ResolveThreads returns the threads a message should be posted to, the main one first.
+// A non-empty forcedThread (such as YOUTUBE_THREAD_ID for videos) is used alone; otherwise the article
+// is matched against the keyword patterns and routed to up to maxTopics topics, falling back to the main thread.
+func ResolveThreads(text utils.ArticleText, forcedThread string, maxTopics int) []Route {
+	if forcedThread != "" {
+		return []Route{{ThreadID: utils.GetEnv(forcedThread), Topic: forcedThread}}
+	}
+
+	// Load keywords from the JSON configuration
+	keywords, err := utils.LoadKeywords(global.KeywordsFile)
+	if err != nil {
+		utils.HandleError(err, "Failed to load keyword patterns", true)
+	}
+
+	// Determine the message threads based on the keywords of the article
+	var routes []Route
+	for _, match := range utils.RouteMatches(utils.ExplainArticle(text, keywords), maxTopics) {
+		routes = append(routes, Route{ThreadID: match.Keyword.ThreadID, Topic: match.Keyword.ThreadEnv, Exclusive: match.Keyword.Exclusive})
+	}
+	if len(routes) == 0 {
+		routes = append(routes, Route{ThreadID: utils.GetEnv("MAIN_THREAD_ID"), Topic: "MAIN_THREAD_ID"})
+	}
+	return routes
+}
+
+// ExclusiveTopics returns the topics of exclusive keyword groups, which never share an article with other topics.
+func ExclusiveTopics() map[string]bool {
+	keywords, err := utils.LoadKeywordsWith(global.KeywordsFile, os.Getenv)
+	if err != nil {
+		utils.HandleError(err, "Failed to load keyword patterns", true)
+	}
+
+	topics := make(map[string]bool)
+	for _, keyword := range keywords {
+		if keyword.Exclusive {
+			topics[keyword.ThreadEnv] = true
 		}
+	}
+	return topics
+}
 
-		// Determine the message thread ID based on title keywords
-		messageThreadID = utils.MatchKeyword(title, keywords, mainThreadID)
+// ChatID returns the chat a topic is posted to: its own chat from the config file, or CHAT_ID.
+func ChatID(topic string) string {
+	if chatID := config.TopicChatID(topic); chatID != "" {
+		return chatID
 	}
+	return utils.GetEnv("CHAT_ID")
+}
 
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken)
+// SendToTelegram sends a message to a thread of the Telegram group using the provided proxy.
+// The thread usually comes from ResolveThreads, and topic is used to pick a per-topic chat from the config file.
+// It handles retries and rate limiting, and returns the ID of the sent message or an error if it could not be delivered.
+func SendToTelegram(message string, proxyURL string, messageThreadID string, topic string) (int64, error) {
 	telegramMessage := TelegramMessage{
-		ChatID:          channelID,
+		ChatID:          ChatID(topic),
 		Text:            message,
 		MessageThreadID: messageThreadID,
 	}
 
+	var sent SentMessage
+	if err := postMessage("sendMessage", telegramMessage, proxyURL, &sent); err != nil {
+		return 0, err
+	}
+	log.Println("Message sent successfully!")
+	return sent.MessageID, nil
+}
+
+// EditTelegramMessage replaces the text of a message sent earlier to the chat of topic.
+func EditTelegramMessage(message string, proxyURL string, messageID int64, topic string) error {
+	telegramMessage := TelegramMessage{
+		ChatID:    ChatID(topic),
+		Text:      message,
+		MessageID: messageID,
+	}
+
+	if err := postMessage("editMessageText", telegramMessage, proxyURL, nil); err != nil {
+		return err
+	}
+	log.Println("Message edited successfully!")
+	return nil
+}
+
+// postMessage calls a Bot API method with a message, retrying on errors and rate limits.
+func postMessage(method string, telegramMessage TelegramMessage, proxyURL string, result any) error {
+	apiURL := APIURL(utils.GetEnv("TELEGRAM_BOT_TOKEN"), method)
+
 	jsonData, err := json.Marshal(telegramMessage)
 	utils.HandleError(err, "Error marshalling Telegram message", false)
 
-	client := utils.CreateHTTPClient(proxyURL)
+	client := utils.SharedHTTPClient(proxyURL)
 	retryCount := 0
 
 	for {
-		err := SendRequest(client, apiURL, jsonData, &retryCount)
+		err := SendRequest(client, apiURL, jsonData, &retryCount, result)
 		if err != nil {
 			if retryCount >= maxRetries {
-				log.Printf("Failed to send message to Telegram after %d retries: %v", maxRetries, err)
-				return
+				log.Printf("Failed to %s after %d retries: %v", method, maxRetries, err)
+				return err
 			}
 			log.Printf("Retrying request (%d/%d): %v", retryCount, maxRetries, err)
 			time.Sleep(retryDelay) // Wait before retrying
 			continue
 		}
-		log.Println("Message sent successfully!")
-		break // Exit the loop if request was successful
+		return nil
 	}
 }
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// ErrNotModified is returned by FetchArticles when a feed has not changed since it was last fetched.
+var ErrNotModified = errors.New("feed not modified")
+
+// FeedCacheEntry holds what is remembered about a feed between fetches.
+type FeedCacheEntry struct {
+	ETag         string
+	LastModified string
+	ContentHash  string
+}
+
+// FeedCacheStore persists the cache entry of every feed.
+type FeedCacheStore interface {
+	Load(feedURL string) (FeedCacheEntry, bool, error)
+	Store(feedURL string, entry FeedCacheEntry) error
+}
+
+// FeedCache is the store used for conditional requests. It is nil, and every feed is fetched in full,
+// unless a run enables it.
+var FeedCache FeedCacheStore
+
+// loadCacheEntry returns the cache entry of a feed, if caching is enabled and the feed was fetched before.
+func loadCacheEntry(feedURL string) (FeedCacheEntry, bool) {
+	if FeedCache == nil {
+		return FeedCacheEntry{}, false
+	}
+	entry, ok, err := FeedCache.Load(feedURL)
+	HandleError(err, "Error loading the feed cache", false)
+	return entry, ok && err == nil
+}
+
+var (
+	heldEntries   = make(map[string]FeedCacheEntry)
+	heldEntriesMu sync.Mutex
+)
+
+// holdCacheEntry keeps the new cache entry of a fetched feed until TakeCacheEntry collects it.
+// It is not stored right away, so a run that dies before handling the items fetches them again next time.
+func holdCacheEntry(feedURL string, entry FeedCacheEntry) {
+	if FeedCache == nil {
+		return
+	}
+	heldEntriesMu.Lock()
+	defer heldEntriesMu.Unlock()
+	heldEntries[feedURL] = entry
+}
+
+// TakeCacheEntry returns and forgets the cache entry held by the last fetch of a feed.
+func TakeCacheEntry(feedURL string) (FeedCacheEntry, bool) {
+	heldEntriesMu.Lock()
+	defer heldEntriesMu.Unlock()
+	entry, ok := heldEntries[feedURL]
+	delete(heldEntries, feedURL)
+	return entry, ok
+}
+
+// StoreCacheEntry saves the cache entry of a feed if caching is enabled.
+// It is called once the items of the feed have been handled.
+func StoreCacheEntry(feedURL string, entry FeedCacheEntry) {
+	if FeedCache != nil {
+		HandleError(FeedCache.Store(feedURL, entry), "Error saving the feed cache", false)
+	}
+}
+
+// setConditionalHeaders asks the server to answer 304 Not Modified if the feed has not changed.
+func setConditionalHeaders(req *http.Request, entry FeedCacheEntry) {
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// ContentHash returns the hex SHA-256 of a feed body, used to detect unchanged feeds from servers without validators.
+func ContentHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MediumCustomDomains are Medium publications served from their own domain.
+var MediumCustomDomains = []string{"infosecwriteups.com"}
+
+// trackingParams are query parameters that only record where a link was clicked, whatever the site.
+// Parameters starting with "utm_" are removed as well. Names that some sites give a meaning, such as
+// ref or source, are left alone; Medium and YouTube links are reduced to the article or video ID anyway.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true, "mkt_tok": true,
+	"igshid": true, "ref_src": true,
+}
+
+// mediumIDPattern matches the 12 character hex ID that ends every Medium article slug.
+var mediumIDPattern = regexp.MustCompile(`(?:^|[-/])([0-9a-f]{12})$`)
+
+// IsMediumHost reports whether a host serves Medium articles.
+func IsMediumHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == "medium.com" || strings.HasSuffix(host, ".medium.com") {
+		return true
+	}
+	for _, domain := range MediumCustomDomains {
+		if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalizeURL returns the form of an article URL used to recognise the same article behind different links.
+// It is only used to compare links: messages keep the URL of the feed. It lower-cases the host, drops the
+// fragment, tracking parameters and trailing slash, and sorts the remaining parameters. Medium articles,
+// whether linked through a user, a publication, a custom domain or the freedium mirror, become
+// https://medium.com/p/<id>, the GUID of Medium feeds, and YouTube videos become
+// https://www.youtube.com/watch?v=<id>. Anything that is not an http(s) URL is returned as is.
+func CanonicalizeURL(raw string) string {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return raw
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+
+	// Undo the freedium rewrite, which either replaces the host or prefixes the whole original URL
+	if host == "freedium.cfd" || strings.HasPrefix(host, "freedium.") {
+		if embedded := strings.TrimPrefix(parsed.Path, "/"); strings.HasPrefix(embedded, "http") {
+			if parsed.RawQuery != "" {
+				embedded += "?" + parsed.RawQuery
+			}
+			return CanonicalizeURL(embedded)
+		}
+		host = "medium.com"
+	}
+
+	path := strings.TrimSuffix(parsed.Path, "/")
+	if IsMediumHost(host) {
+		if m := mediumIDPattern.FindStringSubmatch(path); m != nil {
+			return "https://medium.com/p/" + m[1]
+		}
+	}
+
+	switch host {
+	case "youtu.be":
+		return "https://www.youtube.com/watch?v=" + strings.TrimPrefix(path, "/")
+	case "youtube.com", "www.youtube.com", "m.youtube.com":
+		if strings.HasPrefix(path, "/shorts/") {
+			return "https://www.youtube.com/watch?v=" + strings.TrimPrefix(path, "/shorts/")
+		}
+		if path == "/watch" && parsed.Query().Get("v") != "" {
+			return "https://www.youtube.com/watch?v=" + parsed.Query().Get("v")
+		}
+	}
+
+	query := parsed.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	if path == "" {
+		path = "/"
+	}
+	canonical := url.URL{Scheme: parsed.Scheme, Host: host, Path: path, RawQuery: query.Encode()}
+	if port := parsed.Port(); port != "" && !(parsed.Scheme == "http" && port == "80") && !(parsed.Scheme == "https" && port == "443") {
+		canonical.Host = host + ":" + port
+	}
+	return canonical.String()
+}
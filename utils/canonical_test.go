@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCanonicalizeURL checks that the different links of one article share a canonical URL.
+func TestCanonicalizeURL(t *testing.T) {
+	cases := map[string]string{
+		// Medium through a tag feed, a user, a subdomain, a publication, a custom domain and freedium
+		"https://medium.com/p/4f2c9a1b7d3e?source=rss-----bug_bounty-5":         "https://medium.com/p/4f2c9a1b7d3e",
+		"https://medium.com/@jane/my-first-bug-bounty-4f2c9a1b7d3e":             "https://medium.com/p/4f2c9a1b7d3e",
+		"https://jane.medium.com/my-first-bug-bounty-4f2c9a1b7d3e?sk=abc":       "https://medium.com/p/4f2c9a1b7d3e",
+		"https://medium.com/bugbountywriteup/my-first-bug-bounty-4f2c9a1b7d3e/": "https://medium.com/p/4f2c9a1b7d3e",
+		"https://infosecwriteups.com/my-first-bug-bounty-4f2c9a1b7d3e":          "https://medium.com/p/4f2c9a1b7d3e",
+		"https://freedium.cfd/p/4f2c9a1b7d3e":                                   "https://medium.com/p/4f2c9a1b7d3e",
+		"https://freedium.cfd/https://medium.com/@jane/my-first-4f2c9a1b7d3e":   "https://medium.com/p/4f2c9a1b7d3e",
+		"https://medium.com/tag/bug-bounty":                                     "https://medium.com/tag/bug-bounty",
+		"https://youtu.be/dQw4w9WgXcQ":                                          "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&feature=youtu.be&t=42":     "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		"https://Blog.Example.com:443/post/?utm_source=rss&utm_medium=feed#top": "https://blog.example.com/post",
+		"https://blog.example.com/?utm_source=hn&p=123":                         "https://blog.example.com/?p=123",
+		"https://blog.example.com?p=124&fbclid=x":                               "https://blog.example.com/?p=124",
+		"https://github.com/org/repo/blob/main/README.md?ref=dev":               "https://github.com/org/repo/blob/main/README.md?ref=dev",
+		"note-42": "note-42",
+	}
+
+	for raw, expected := range cases {
+		assert.Equal(t, expected, CanonicalizeURL(raw), raw)
+	}
+}
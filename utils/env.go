@@ -5,14 +5,19 @@ import (
 	"path/filepath"
 
 	"github.com/joho/godotenv"
+	"github.com/sirupsen/logrus"
 )
 
 // LoadEnv loads environment variables from a `.env` file located in the specified directory.
-// If the `.env` file cannot be loaded, it logs a fatal error and exits the program.
+// A missing `.env` file is not an error, since the values may come from the process environment or the config file.
 // The `.env` file path is determined by joining the `GITHUB_WORKSPACE` environment variable with `.env`.
 func LoadEnv() {
 	envFile := filepath.Join(os.Getenv("GITHUB_WORKSPACE"), ".env")
 	err := godotenv.Load(envFile)
+	if os.IsNotExist(err) {
+		logrus.Debugf("No %s file found, using the process environment.", envFile)
+		return
+	}
 
-	HandleError(err, "Error loading .env file:", true)
+	HandleError(err, "Error loading .env file:", false)
 }
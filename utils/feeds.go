@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ValidateFeedURL checks that a feed entry is an absolute http(s) URL without surrounding whitespace.
+func ValidateFeedURL(feedURL string) error {
+	if feedURL != strings.TrimSpace(feedURL) {
+		return fmt.Errorf("surrounding whitespace")
+	}
+
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme: %q", parsed.Scheme)
+	}
+	if parsed.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
+
+// DedupeUrls removes repeated feeds while keeping the order of first appearance.
+func DedupeUrls(urls []string) []string {
+	return UniqueStrings(urls)
+}
+
+// AppendUrls adds feeds at the end of the URL file, one per line, leaving the existing lines as they are.
+func AppendUrls(filePath string, urls []string) error {
+	content, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var b strings.Builder
+	b.Write(content)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		b.WriteString("\n")
+	}
+	for _, u := range urls {
+		b.WriteString(u + "\n")
+	}
+	return os.WriteFile(filePath, []byte(b.String()), 0644)
+}
+
+// RemoveUrls deletes the lines of the URL file holding one of the given feeds, ignoring surrounding whitespace,
+// and leaves every other line as it is. It returns the feeds that were found, in file order.
+func RemoveUrls(filePath string, urls []string) ([]string, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	remove := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		remove[strings.TrimSpace(u)] = true
+	}
+
+	var b strings.Builder
+	var removed []string
+	for _, line := range strings.SplitAfter(string(content), "\n") {
+		if u := strings.TrimSpace(line); u != "" && remove[u] {
+			removed = append(removed, u)
+			continue
+		}
+		b.WriteString(line)
+	}
+	return DedupeUrls(removed), os.WriteFile(filePath, []byte(b.String()), 0644)
+}
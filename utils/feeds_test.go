@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAppendAndRemoveUrls checks that adding and removing feeds leaves the other lines of the file untouched.
+func TestAppendAndRemoveUrls(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "url.txt")
+	original := "https://medium.com/feed/tag/xss\n\nhttps://hashnode.com/n/recon/rss\nhttps://example.com/feed"
+	assert.NoError(t, os.WriteFile(path, []byte(original), 0644))
+
+	assert.NoError(t, AppendUrls(path, []string{"https://dev.to/feed/tag/security"}))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, original+"\nhttps://dev.to/feed/tag/security\n", string(content))
+
+	removed, err := RemoveUrls(path, []string{"https://hashnode.com/n/recon/rss", "https://example.org/missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://hashnode.com/n/recon/rss"}, removed)
+	content, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://medium.com/feed/tag/xss\n\nhttps://example.com/feed\nhttps://dev.to/feed/tag/security\n", string(content))
+}
@@ -6,105 +6,355 @@ import (
 	"os"
 	"regexp"
 	"sort"
+	"strings"
+
+	"writeup-finder.go/config"
+)
+
+// Article fields that keyword rules can target.
+const (
+	FieldTitle       = "title"
+	FieldCategories  = "categories"
+	FieldDescription = "description"
+	FieldContent     = "content"
 )
 
-// KeywordPattern represents a compiled regex pattern, its associated thread ID, and priority.
+// DefaultFieldWeights is what a match in each field adds to the score of a rule, unless field_weights
+// in the config file says otherwise. Title matches weigh the most, so they win over matches in the body.
+var DefaultFieldWeights = map[string]int{
+	FieldTitle:       10,
+	FieldCategories:  5,
+	FieldDescription: 3,
+	FieldContent:     1,
+}
+
+// ArticleText is the plain text of an article that keyword rules are matched against.
+type ArticleText struct {
+	Title       string
+	Description string
+	Content     string
+	Categories  []string
+}
+
+// Field returns the text of one field, with the categories one per line.
+func (t ArticleText) Field(name string) string {
+	switch name {
+	case FieldTitle:
+		return t.Title
+	case FieldDescription:
+		return t.Description
+	case FieldContent:
+		return t.Content
+	case FieldCategories:
+		return strings.Join(t.Categories, "\n")
+	}
+	return ""
+}
+
+// FieldWeight returns the weight of a field from the config file, or its default weight.
+func FieldWeight(field string) int {
+	if weight, ok := config.Current().FieldWeights[field]; ok {
+		return weight
+	}
+	return DefaultFieldWeights[field]
+}
+
+// KeywordPattern represents a compiled regex pattern, its associated thread ID, priority and the fields it targets.
+// Group and ThreadEnv record where the pattern came from so matches can be explained.
 type KeywordPattern struct {
-	Pattern  *regexp.Regexp
-	ThreadID string
-	Priority int
+	Pattern   *regexp.Regexp
+	ThreadID  string
+	Priority  int
+	Fields    []string
+	Group     string
+	Exclusive bool
+	ThreadEnv string
 }
 
-// RawKeyword represents a keyword pattern and its associated thread ID and priority as loaded from JSON.
-type RawKeyword struct {
-	Pattern  string `json:"pattern"`
-	ThreadID string `json:"threadID"`
-	Priority int    `json:"priority"`
+// KeywordMatch describes one keyword pattern that matched an article.
+// Field, Matched and Weight tell the heaviest field the pattern matched, and Score adds up the weights of all of them.
+type KeywordMatch struct {
+	Keyword KeywordPattern
+	Field   string
+	Matched string
+	Weight  int
+	Score   int
+	Winner  bool
 }
 
-// KeywordGroup represents a group of keywords with a common name.
-type KeywordGroup struct {
-	Name     string       `json:"name"`
-	Keywords []RawKeyword `json:"keywords"`
+// ThreadIDEnvNames lists the built-in environment variables that may be referenced as threadID in keywords.json.
+// Topics declared in the config file are accepted as well, by name or by env.
+var ThreadIDEnvNames = []string{
+	"MONEY_THREAD_ID",
+	"BYPASS_THREAD_ID",
+	"PLATFORMS_THREAD_ID",
+	"TRYHACKME_THREAD_ID",
+	"HACKTHEBOX_THREAD_ID",
+	"MOBILE_THREAD_ID",
+	"RECON_THREAD_ID",
+	"PORTSWIGGER_THREAD_ID",
+	"BURPSUITE_THREAD_ID",
+	"CTF_THREAD_ID",
+	"OS_THREAD_ID",
+	"VULNERABILITIES_THREAD_ID",
+	"TOOLS_THREAD_ID",
+	"PROGRAMMINGLANGS_THREAD_ID",
+	"CVE_THREAD_ID",
+	"OSINT_THREAD_ID",
+	"CRYPTOGRAPHIC_THREAD_ID",
+	"STEGANOGRAPHY_THREAD_ID",
+	"WEBSCRAPING_THREAD_ID",
 }
 
+// RawKeyword represents a keyword pattern and its associated thread ID and priority as loaded from JSON.
+type RawKeyword = config.Keyword
+
+// KeywordGroup represents a group of keywords with a common name.
+type KeywordGroup = config.KeywordGroup
+
 // LoadKeywords loads keyword patterns from a JSON configuration file and compiles them into regex patterns.
+// Keyword groups declared in the config file are appended to the ones from the JSON file.
 // It also maps thread IDs from environment variables and sorts the keywords by priority.
 // Returns a slice of KeywordPattern or an error if the file cannot be read or the regex cannot be compiled.
 func LoadKeywords(configPath string) ([]KeywordPattern, error) {
-	// Map thread IDs from environment variables
-	threadIDMap := map[string]string{
-		"MONEY_THREAD_ID":            GetEnv("MONEY_THREAD_ID"),
-		"BYPASS_THREAD_ID":           GetEnv("BYPASS_THREAD_ID"),
-		"PLATFORMS_THREAD_ID":        GetEnv("PLATFORMS_THREAD_ID"),
-		"TRYHACKME_THREAD_ID":        GetEnv("TRYHACKME_THREAD_ID"),
-		"HACKTHEBOX_THREAD_ID":       GetEnv("HACKTHEBOX_THREAD_ID"),
-		"MOBILE_THREAD_ID":           GetEnv("MOBILE_THREAD_ID"),
-		"RECON_THREAD_ID":            GetEnv("RECON_THREAD_ID"),
-		"PORTSWIGGER_THREAD_ID":      GetEnv("PORTSWIGGER_THREAD_ID"),
-		"BURPSUITE_THREAD_ID":        GetEnv("BURPSUITE_THREAD_ID"),
-		"CTF_THREAD_ID":              GetEnv("CTF_THREAD_ID"),
-		"OS_THREAD_ID":               GetEnv("OS_THREAD_ID"),
-		"VULNERABILITIES_THREAD_ID":  GetEnv("VULNERABILITIES_THREAD_ID"),
-		"TOOLS_THREAD_ID":            GetEnv("TOOLS_THREAD_ID"),
-		"PROGRAMMINGLANGS_THREAD_ID": GetEnv("PROGRAMMINGLANGS_THREAD_ID"),
-		"CVE_THREAD_ID":              GetEnv("CVE_THREAD_ID"),
-		"OSINT_THREAD_ID":            GetEnv("OSINT_THREAD_ID"),
-		"CRYPTOGRAPHIC_THREAD_ID":    GetEnv("CRYPTOGRAPHIC_THREAD_ID"),
-		"STEGANOGRAPHY_THREAD_ID":    GetEnv("STEGANOGRAPHY_THREAD_ID"),
-		"WEBSCRAPING_THREAD_ID":      GetEnv("WEBSCRAPING_THREAD_ID"),
-	}
+	return LoadKeywordsWith(configPath, GetEnv)
+}
 
-	// Load JSON configuration file
-	file, err := os.Open(configPath)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
+// LoadKeywordsWith behaves like LoadKeywords but resolves thread IDs through the given lookup function.
+// Passing os.Getenv loads the patterns without warning about unset thread variables.
+func LoadKeywordsWith(configPath string, lookup func(string) string) ([]KeywordPattern, error) {
+	threadEnvMap, envNames := threadReferences()
 
-	var rawConfig struct {
-		Groups []KeywordGroup `json:"groups"`
+	// Resolve every thread ID once
+	threadIDMap := make(map[string]string, len(envNames))
+	for _, env := range envNames {
+		threadIDMap[env] = lookup(env)
 	}
 
-	if err := json.NewDecoder(file).Decode(&rawConfig); err != nil {
+	groups, err := readKeywordGroups(configPath)
+	if err != nil {
 		return nil, err
 	}
 
 	// Parse keywords and compile regex patterns
 	var keywords []KeywordPattern
-	for _, group := range rawConfig.Groups {
+	for _, group := range groups {
 		for _, raw := range group.Keywords {
 			compiledPattern, err := regexp.Compile("(?i)" + raw.Pattern)
 			if err != nil {
 				return nil, err // Return an error if regex compilation fails
 			}
-			threadID, ok := threadIDMap[raw.ThreadID]
+			threadEnv, ok := threadEnvMap[raw.ThreadID]
 			if !ok {
 				return nil, fmt.Errorf("unknown thread ID: %s", raw.ThreadID)
 			}
+			if err := validateFields(raw.Fields); err != nil {
+				return nil, err
+			}
 			keywords = append(keywords, KeywordPattern{
-				Pattern:  compiledPattern,
-				ThreadID: threadID,
-				Priority: raw.Priority,
+				Pattern:   compiledPattern,
+				ThreadID:  threadIDMap[threadEnv],
+				Priority:  raw.Priority,
+				Fields:    keywordFields(raw),
+				Group:     group.Name,
+				Exclusive: group.Exclusive,
+				ThreadEnv: threadEnv,
 			})
 		}
 	}
 
-	// Sort keywords by priority (ascending order)
-	sort.Slice(keywords, func(i, j int) bool {
+	// Sort keywords by priority (ascending order), keeping file order for equal priorities
+	sort.SliceStable(keywords, func(i, j int) bool {
 		return keywords[i].Priority < keywords[j].Priority
 	})
 
 	return keywords, nil
 }
 
-// MatchKeyword searches for the first keyword pattern that matches the given title.
+// ValidateKeywords checks every keyword rule without stopping at the first problem.
+// It reports patterns that do not compile, unknown thread references and thread IDs that resolve to nothing.
+func ValidateKeywords(configPath string, lookup func(string) string) []error {
+	groups, err := readKeywordGroups(configPath)
+	if err != nil {
+		return []error{err}
+	}
+
+	threadEnvMap, _ := threadReferences()
+	unset := make(map[string]bool)
+	var errs []error
+	for _, group := range groups {
+		for _, raw := range group.Keywords {
+			if _, err := regexp.Compile("(?i)" + raw.Pattern); err != nil {
+				errs = append(errs, fmt.Errorf("group %s: invalid pattern %q: %w", group.Name, raw.Pattern, err))
+			}
+			if err := validateFields(raw.Fields); err != nil {
+				errs = append(errs, fmt.Errorf("group %s: %w", group.Name, err))
+			}
+			threadEnv, ok := threadEnvMap[raw.ThreadID]
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Errorf("group %s: unknown thread ID: %s", group.Name, raw.ThreadID))
+			case lookup(threadEnv) == "" && !unset[threadEnv]:
+				unset[threadEnv] = true
+				errs = append(errs, fmt.Errorf("group %s: thread %s is not set", group.Name, threadEnv))
+			}
+		}
+	}
+	return errs
+}
+
+// keywordFields returns the fields a rule targets, the title when it lists none.
+func keywordFields(raw RawKeyword) []string {
+	if len(raw.Fields) == 0 {
+		return []string{FieldTitle}
+	}
+	return raw.Fields
+}
+
+// validateFields checks that every field of a rule is one that can be matched.
+func validateFields(fields []string) error {
+	for _, field := range fields {
+		if _, ok := DefaultFieldWeights[field]; !ok {
+			return fmt.Errorf("unknown field: %s", field)
+		}
+	}
+	return nil
+}
+
+// threadReferences maps every accepted thread reference to the environment variable holding the thread ID.
+// It also returns the distinct variable names in a stable order.
+func threadReferences() (map[string]string, []string) {
+	threadEnvMap := make(map[string]string, len(ThreadIDEnvNames))
+	envNames := append([]string{}, ThreadIDEnvNames...)
+	for _, name := range ThreadIDEnvNames {
+		threadEnvMap[name] = name
+	}
+	for _, topic := range config.Current().Topics {
+		if _, ok := threadEnvMap[topic.EnvName()]; !ok {
+			envNames = append(envNames, topic.EnvName())
+		}
+		threadEnvMap[topic.EnvName()] = topic.EnvName()
+		if topic.Name != "" {
+			threadEnvMap[topic.Name] = topic.EnvName()
+		}
+	}
+	return threadEnvMap, envNames
+}
+
+// readKeywordGroups loads the keyword groups of the JSON file followed by the ones from the config file.
+func readKeywordGroups(configPath string) ([]KeywordGroup, error) {
+	// Load JSON configuration file
+	file, err := os.Open(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rawConfig struct {
+		Groups []KeywordGroup `json:"groups"`
+	}
+
+	if err := json.NewDecoder(file).Decode(&rawConfig); err != nil {
+		return nil, err
+	}
+
+	return append(rawConfig.Groups, config.Current().KeywordGroups...), nil
+}
+
+// MatchKeyword searches for the best keyword pattern that matches the given title.
 // It returns the associated thread ID if a match is found, otherwise returns the default thread ID.
 func MatchKeyword(title string, keywords []KeywordPattern, defaultThreadID string) string {
+	if keyword := MatchKeywordPattern(title, keywords); keyword != nil {
+		return keyword.ThreadID
+	}
+	return defaultThreadID
+}
+
+// MatchKeywordPattern returns the best keyword pattern that matches the given title, or nil if none does.
+// Only rules that target the title can match.
+func MatchKeywordPattern(title string, keywords []KeywordPattern) *KeywordPattern {
+	return MatchArticle(ArticleText{Title: title}, keywords)
+}
+
+// MatchArticle returns the keyword pattern with the highest score for the article, or nil if none matches.
+func MatchArticle(text ArticleText, keywords []KeywordPattern) *KeywordPattern {
+	matches := ExplainArticle(text, keywords)
+	if len(matches) == 0 {
+		return nil
+	}
+	return &matches[0].Keyword
+}
+
+// RouteMatches picks the matches an article is posted to: the winner followed, in multi-route mode, by the best
+// matches of other topics, up to maxTopics in total. A match of an exclusive group is never shared: when it wins
+// the article goes to its topic only, and otherwise it is left out.
+func RouteMatches(matches []KeywordMatch, maxTopics int) []KeywordMatch {
+	if len(matches) == 0 {
+		return nil
+	}
+
+	routes := []KeywordMatch{matches[0]}
+	if matches[0].Keyword.Exclusive {
+		return routes
+	}
+
+	seen := map[string]bool{matches[0].Keyword.ThreadEnv: true}
+	for _, match := range matches[1:] {
+		if len(routes) >= maxTopics {
+			break
+		}
+		if match.Keyword.Exclusive || seen[match.Keyword.ThreadEnv] {
+			continue
+		}
+		seen[match.Keyword.ThreadEnv] = true
+		routes = append(routes, match)
+	}
+	return routes
+}
+
+// ExplainKeywords returns every keyword pattern that matches the given title, best first.
+func ExplainKeywords(title string, keywords []KeywordPattern) []KeywordMatch {
+	return ExplainArticle(ArticleText{Title: title}, keywords)
+}
+
+// ExplainArticle returns every keyword pattern that matches the fields it targets in the article, ordered by
+// the weight of the heaviest field matched, then by priority, then by score. A title match of a higher priority
+// rule thus wins over a rule matching the title and the body. The first match is flagged as the winner,
+// since that is the one MatchArticle would return.
+func ExplainArticle(text ArticleText, keywords []KeywordPattern) []KeywordMatch {
+	var matches []KeywordMatch
 	for _, keyword := range keywords {
-		if keyword.Pattern.MatchString(title) {
-			return keyword.ThreadID
+		match := KeywordMatch{Keyword: keyword}
+		for _, field := range keyword.Fields {
+			value := text.Field(field)
+			loc := keyword.Pattern.FindStringIndex(value)
+			if loc == nil {
+				continue
+			}
+			weight := FieldWeight(field)
+			match.Score += weight
+			if match.Field == "" || weight > match.Weight {
+				match.Field, match.Matched, match.Weight = field, value[loc[0]:loc[1]], weight
+			}
+		}
+		if match.Score > 0 {
+			matches = append(matches, match)
 		}
 	}
-	return defaultThreadID
+
+	// A stable sort keeps the file order of rules that tie on everything
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Weight != b.Weight {
+			return a.Weight > b.Weight
+		}
+		if a.Keyword.Priority != b.Keyword.Priority {
+			return a.Keyword.Priority < b.Keyword.Priority
+		}
+		return a.Score > b.Score
+	})
+	if len(matches) > 0 {
+		matches[0].Winner = true
+	}
+	return matches
 }
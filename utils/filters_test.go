@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExplainKeywords checks that every matching rule is reported and the highest priority one wins.
+func TestExplainKeywords(t *testing.T) {
+	keywords, err := LoadKeywordsWith("../data/keywords.json", os.Getenv)
+	assert.NoError(t, err)
+
+	matches := ExplainKeywords("How I got $500 with a WAF bypass", keywords)
+	assert.Len(t, matches, 2)
+
+	assert.True(t, matches[0].Winner)
+	assert.Equal(t, "MONEY_THREAD_ID", matches[0].Keyword.ThreadEnv)
+	assert.Equal(t, "general", matches[0].Keyword.Group)
+	assert.Equal(t, "$500", matches[0].Matched)
+
+	assert.False(t, matches[1].Winner)
+	assert.Equal(t, "BYPASS_THREAD_ID", matches[1].Keyword.ThreadEnv)
+	assert.Equal(t, "WAF", matches[1].Matched)
+
+	assert.Empty(t, ExplainKeywords("hello world", keywords))
+}
+
+// TestExplainArticle checks that rules targeting the body match it, and that a title match outranks them.
+func TestExplainArticle(t *testing.T) {
+	keywords, err := LoadKeywordsWith("../data/keywords.json", os.Getenv)
+	assert.NoError(t, err)
+
+	text := ArticleText{
+		Title:      "Notes from a long weekend",
+		Content:    "The WAF blocked every payload until a unicode trick let me bypass it.",
+		Categories: []string{"Reconnaissance"},
+	}
+	matches := ExplainArticle(text, keywords)
+	assert.Len(t, matches, 2)
+	assert.Equal(t, "RECON_THREAD_ID", matches[0].Keyword.ThreadEnv, "a category match outweighs a content match")
+	assert.Equal(t, FieldCategories, matches[0].Field)
+	assert.Equal(t, DefaultFieldWeights[FieldCategories], matches[0].Score)
+	assert.Equal(t, "BYPASS_THREAD_ID", matches[1].Keyword.ThreadEnv)
+
+	text.Title = "How I earned my first bounty"
+	winner := MatchArticle(text, keywords)
+	if assert.NotNil(t, winner) {
+		assert.Equal(t, "MONEY_THREAD_ID", winner.ThreadEnv)
+	}
+
+	// The bypass rule matches the title and the body, the money rule only the title but with a higher priority
+	matches = ExplainArticle(ArticleText{
+		Title:   "How I got $500 with a WAF bypass",
+		Content: "The bypass relied on a unicode trick.",
+	}, keywords)
+	assert.Equal(t, "MONEY_THREAD_ID", matches[0].Keyword.ThreadEnv)
+	assert.Equal(t, "BYPASS_THREAD_ID", matches[1].Keyword.ThreadEnv)
+	assert.Greater(t, matches[1].Score, matches[0].Score)
+}
+
+// TestRouteMatches checks that multi-route mode adds the next topics up to the limit and respects exclusive groups.
+func TestRouteMatches(t *testing.T) {
+	match := func(env string, exclusive bool) KeywordMatch {
+		return KeywordMatch{Keyword: KeywordPattern{ThreadEnv: env, Exclusive: exclusive}}
+	}
+	matches := []KeywordMatch{
+		match("HACKTHEBOX_THREAD_ID", false),
+		match("HACKTHEBOX_THREAD_ID", false),
+		match("MONEY_THREAD_ID", true),
+		match("CVE_THREAD_ID", false),
+		match("RECON_THREAD_ID", false),
+	}
+
+	assert.Len(t, RouteMatches(matches, 1), 1)
+
+	routes := RouteMatches(matches, 2)
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "CVE_THREAD_ID", routes[1].Keyword.ThreadEnv, "duplicates and exclusive groups are skipped")
+
+	assert.Len(t, RouteMatches(matches[2:], 3), 1, "an exclusive winner is not shared")
+	assert.Empty(t, RouteMatches(nil, 3))
+}
@@ -3,9 +3,15 @@ package utils
 import (
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 )
 
+var (
+	httpClients   = map[string]*http.Client{}
+	httpClientsMu sync.Mutex
+)
+
 // CreateHTTPClient creates and returns an HTTP client with a 30-second timeout.
 // If a proxy URL is provided, it configures the client to use the proxy.
 // If the proxy URL is invalid, the function logs an error and returns a client without proxy settings.
@@ -27,3 +33,17 @@ func CreateHTTPClient(proxyURL string) *http.Client {
 
 	return client
 }
+
+// SharedHTTPClient returns a client for the given proxy URL, creating it on first use.
+// Reusing the client keeps its connection pool alive across messages and passes.
+func SharedHTTPClient(proxyURL string) *http.Client {
+	httpClientsMu.Lock()
+	defer httpClientsMu.Unlock()
+
+	client, ok := httpClients[proxyURL]
+	if !ok {
+		client = CreateHTTPClient(proxyURL)
+		httpClients[proxyURL] = client
+	}
+	return client
+}
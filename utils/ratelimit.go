@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket is a token-bucket rate limiter safe for concurrent use.
+// It holds up to burst tokens and refills at rate tokens per second.
+type TokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	burst  float64
+	rate   float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a full bucket refilling at rate tokens per second.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	return &TokenBucket{tokens: float64(burst), burst: float64(burst), rate: rate, last: time.Now()}
+}
+
+// Wait blocks until a token is available and takes it.
+func (b *TokenBucket) Wait() {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return
+		}
+		wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		time.Sleep(wait)
+	}
+}
+
+// RateBudget is the request rate allowed for one group of hosts.
+type RateBudget struct {
+	Rate  float64 // Requests per second
+	Burst int     // Requests allowed at once after an idle period
+}
+
+// HostLimiter keeps one token bucket per budget key so each platform is throttled independently.
+type HostLimiter struct {
+	mu       sync.Mutex
+	key      func(rawURL string) string
+	buckets  map[string]*TokenBucket
+	budgets  map[string]RateBudget
+	fallback RateBudget
+}
+
+// NewHostLimiter returns a limiter that groups URLs by key, using the given budgets per key
+// and fallback for every other key.
+func NewHostLimiter(key func(rawURL string) string, budgets map[string]RateBudget, fallback RateBudget) *HostLimiter {
+	return &HostLimiter{
+		key:      key,
+		buckets:  make(map[string]*TokenBucket),
+		budgets:  budgets,
+		fallback: fallback,
+	}
+}
+
+// Wait blocks until a request to rawURL is allowed by the budget of its key.
+func (l *HostLimiter) Wait(rawURL string) {
+	key := l.key(rawURL)
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		budget, ok := l.budgets[key]
+		if !ok {
+			budget = l.fallback
+		}
+		bucket = NewTokenBucket(budget.Rate, budget.Burst)
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+
+	bucket.Wait()
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTokenBucket checks that the burst is served at once and later requests wait for a refill.
+func TestTokenBucket(t *testing.T) {
+	bucket := NewTokenBucket(20, 2)
+
+	start := time.Now()
+	bucket.Wait()
+	bucket.Wait()
+	assert.Less(t, time.Since(start), 20*time.Millisecond)
+
+	bucket.Wait()
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
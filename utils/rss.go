@@ -1,20 +1,27 @@
 package utils
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/mmcdole/gofeed"
 )
 
-// FetchArticles retrieves articles from the given RSS feed URL.
+// FeedClient is the HTTP client shared by every feed fetch so connections are reused between requests and passes.
+var FeedClient = &http.Client{
+	Timeout: time.Second * 10, // Set a timeout for the request
+}
+
+// FetchArticles retrieves articles from the given RSS, Atom or JSON Feed URL.
+// Items without an author inherit the author of the feed, as both Atom and JSON Feed 1.1 specify.
+// When FeedCache is set, the request is conditional and ErrNotModified is returned without parsing
+// if the server answers 304 or the body hashes the same as last time. The new validators are held
+// for TakeCacheEntry rather than stored, so the caller saves them once the items are handled.
 // It returns a list of feed items or an error if the request or parsing fails.
 func FetchArticles(feedURL string) ([]*gofeed.Item, error) {
-	client := &http.Client{
-		Timeout: time.Second * 10, // Set a timeout for the request
-	}
-
 	req, err := http.NewRequest("GET", feedURL, nil)
 	if err != nil {
 		HandleError(err, "Error creating request", false)
@@ -23,15 +30,24 @@ func FetchArticles(feedURL string) ([]*gofeed.Item, error) {
 
 	// Set headers to mimic a browser request
 	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:91.0) Gecko/20100101 Firefox/91.0")
-	req.Header.Set("Accept", "application/rss+xml, application/xml;q=0.9, */*;q=0.8")
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/feed+json, application/xml;q=0.9, application/json;q=0.9, */*;q=0.8")
+
+	cached, hasCache := loadCacheEntry(feedURL)
+	if hasCache {
+		setConditionalHeaders(req, cached)
+	}
 
-	resp, err := client.Do(req)
+	resp, err := FeedClient.Do(req)
 	if err != nil {
 		HandleError(err, "Error fetching feed", false)
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
 	// Check for non-2xx HTTP status codes
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		err := fmt.Errorf("HTTP error: %s, status code: %d", resp.Status, resp.StatusCode)
@@ -39,8 +55,24 @@ func FetchArticles(feedURL string) ([]*gofeed.Item, error) {
 		return nil, err
 	}
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		HandleError(err, "Error reading feed", false)
+		return nil, err
+	}
+
+	entry := FeedCacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentHash:  ContentHash(body),
+	}
+	if hasCache && cached.ContentHash == entry.ContentHash {
+		holdCacheEntry(feedURL, entry)
+		return nil, ErrNotModified
+	}
+
 	parser := gofeed.NewParser()
-	feed, err := parser.Parse(resp.Body)
+	feed, err := parser.Parse(bytes.NewReader(body))
 	if err != nil {
 		HandleError(err, "Error parsing feed", false)
 		return nil, err
@@ -52,19 +84,12 @@ func FetchArticles(feedURL string) ([]*gofeed.Item, error) {
 		return nil, err
 	}
 
-	return feed.Items, nil
-}
-
-// ParseDate attempts to parse a date string using RFC1123Z or RFC1123 formats.
-// It returns the parsed time.Time object and any error encountered during parsing.
-func ParseDate(dateString string) (time.Time, error) {
-	parsedTime, err := time.Parse(time.RFC1123Z, dateString)
-	if err != nil {
-		// Attempt parsing with an alternative format
-		parsedTime, err = time.Parse(time.RFC1123, dateString)
+	for _, item := range feed.Items {
+		if item.Author == nil && len(item.Authors) == 0 {
+			item.Author, item.Authors = feed.Author, feed.Authors
+		}
 	}
 
-	HandleError(err, "Error parsing date", false)
-
-	return parsedTime, err
+	holdCacheEntry(feedURL, entry)
+	return feed.Items, nil
 }
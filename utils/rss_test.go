@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryCache is a FeedCacheStore kept in memory.
+type memoryCache map[string]FeedCacheEntry
+
+// Load returns the entry stored for the feed.
+func (c memoryCache) Load(feedURL string) (FeedCacheEntry, bool, error) {
+	entry, ok := c[feedURL]
+	return entry, ok, nil
+}
+
+// Store keeps the entry of the feed.
+func (c memoryCache) Store(feedURL string, entry FeedCacheEntry) error {
+	c[feedURL] = entry
+	return nil
+}
+
+// cachedFeed is the body served by the test server.
+const cachedFeed = `<?xml version="1.0"?><rss version="2.0"><channel><title>t</title>
+<item><title>a</title><link>https://example.com/a</link></item></channel></rss>`
+
+// TestFetchArticlesConditional checks that validators are sent back and that 304 responses
+// and unchanged bodies are reported as ErrNotModified.
+func TestFetchArticlesConditional(t *testing.T) {
+	var ifNoneMatch string
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		if etag != "" && ifNoneMatch == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(cachedFeed))
+	}))
+	defer server.Close()
+
+	cache := memoryCache{}
+	FeedCache = cache
+	defer func() { FeedCache = nil }()
+
+	items, err := FetchArticles(server.URL)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Empty(t, ifNoneMatch)
+	assert.Empty(t, cache, "nothing is stored before the items are handled")
+
+	entry, ok := TakeCacheEntry(server.URL)
+	assert.True(t, ok)
+	assert.Equal(t, `"v1"`, entry.ETag)
+	StoreCacheEntry(server.URL, entry)
+
+	_, err = FetchArticles(server.URL)
+	assert.ErrorIs(t, err, ErrNotModified)
+	assert.Equal(t, `"v1"`, ifNoneMatch)
+
+	// A server without validators that returns the same body
+	etag = ""
+	_, err = FetchArticles(server.URL)
+	assert.ErrorIs(t, err, ErrNotModified)
+	entry, ok = TakeCacheEntry(server.URL)
+	assert.True(t, ok)
+	assert.Empty(t, entry.ETag)
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+)
+
+// wordPattern finds the words of a text, in any script.
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// stopWords are left out of fingerprints since they say nothing about the topic.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true, "in": true, "on": true, "to": true,
+	"for": true, "with": true, "by": true, "how": true, "i": true, "my": true, "is": true, "it": true,
+	"at": true, "from": true, "this": true, "that": true, "or": true, "medium": true,
+}
+
+// Simhash returns a 64-bit fingerprint of a text, such that similar texts have fingerprints that differ in few bits.
+// The text is lower-cased and split into words, ignoring punctuation and stop words, and every word
+// and pair of consecutive words contributes to the fingerprint.
+func Simhash(text string) uint64 {
+	var words []string
+	for _, word := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		if !stopWords[word] {
+			words = append(words, word)
+		}
+	}
+
+	var weights [64]int
+	add := func(feature string) {
+		hash := fnv.New64a()
+		hash.Write([]byte(feature))
+		sum := hash.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+	for i, word := range words {
+		add(word)
+		if i > 0 {
+			add(words[i-1] + " " + word)
+		}
+	}
+
+	var fingerprint uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			fingerprint |= 1 << bit
+		}
+	}
+	return fingerprint
+}
+
+// HammingDistance returns the number of bits that differ between two fingerprints.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
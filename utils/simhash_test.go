@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSimhash checks that a cross-post with a retouched title stays close and an unrelated post does not.
+func TestSimhash(t *testing.T) {
+	description := "While testing the billing API of a private program I noticed that invoice IDs were sequential " +
+		"and the endpoint never checked the owner, which let me download any customer's invoices."
+
+	original := Simhash("How I found an IDOR in the invoices API " + description)
+	crossPost := Simhash("How I Found an IDOR in the Invoices API | by Jane " + description)
+	unrelated := Simhash("Bypassing Cloudflare WAF with unicode normalization Web application firewalls normalise " +
+		"input differently than the backend, and this post shows how to abuse that to smuggle XSS payloads.")
+
+	assert.LessOrEqual(t, HammingDistance(original, crossPost), 8)
+	assert.Greater(t, HammingDistance(original, unrelated), 8)
+}
@@ -8,10 +8,11 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"writeup-finder.go/config"
 )
 
 // ReadUrls reads a list of URLs from a text file and returns them as a slice of strings.
-// It trims whitespace and skips empty lines. If the file cannot be opened or read, it logs an error.
+// It trims whitespace, skips empty lines and drops duplicates. If the file cannot be opened or read, it logs an error.
 func ReadUrls(filePath string) []string {
 	file, err := os.Open(filePath)
 	HandleError(err, "Error opening URL file", false)
@@ -30,7 +31,7 @@ func ReadUrls(filePath string) []string {
 		HandleError(err, "Error scanning URL file", false)
 	}
 
-	return urls
+	return DedupeUrls(urls)
 }
 
 // HandleError logs an error message with optional coloring and exits the program if specified.
@@ -62,12 +63,26 @@ func PrintPretty(message string, colorAttr color.Attribute, isTitle bool) {
 	}
 }
 
-// GetEnv retrieves the value of an environment variable.
-// If the variable is not set, it logs an error and returns an empty string.
+// GetEnv retrieves the value of a setting from the environment, falling back to the config file.
+// If the setting is not defined anywhere, it logs an error and returns an empty string.
 func GetEnv(key string) string {
-	value := os.Getenv(key)
+	value := config.Lookup(key)
 	if value == "" {
 		HandleError(fmt.Errorf("environment variable %s not set", key), "Missing environment variable", false)
 	}
 	return value
 }
+
+// UniqueStrings returns the values without repeats, in the order of their first appearance.
+func UniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}